@@ -0,0 +1,88 @@
+package gojwt
+
+import (
+	"encoding/json"
+	"strings"
+)
+
+// Audience is the aud (Audience) claim of a JWT, which is either a single string or an array of strings.
+// It implements the json.Marshaler and json.Unmarshaler interface and keeps the form
+// of the claim, so a single string is formatted as a string and an array as an array.
+type Audience struct {
+
+	// Values holds the audiences the JWT is intended for.
+	Values []string
+
+	// array indicates whether the claim is formatted as an array, even if it only holds a single value.
+	array bool
+}
+
+// NewAudience creates an Audience from a list of audiences.
+// A single audience is formatted as a string, multiple audiences as an array.
+func NewAudience(audiences ...string) *Audience {
+	return &Audience{
+		Values: audiences,
+		array:  len(audiences) != 1,
+	}
+}
+
+// Contains returns a bool, whether the Audience contains a given audience.
+func (this *Audience) Contains(audience string) bool {
+	return this != nil && contains(this.Values, audience)
+}
+
+// ContainsAny returns a bool, whether the Audience contains any of the given audiences.
+func (this *Audience) ContainsAny(audiences ...string) bool {
+	for _, audience := range audiences {
+		if this.Contains(audience) {
+			return true
+		}
+	}
+	return false
+}
+
+// IsEmpty returns a bool, whether the Audience holds any values or not.
+func (this *Audience) IsEmpty() bool {
+	return this == nil || len(this.Values) == 0
+}
+
+// String formats the Audience into a comma separated list.
+func (this *Audience) String() string {
+	if this == nil {
+		return ""
+	}
+	return strings.Join(this.Values, ",")
+}
+
+// MarshalJSON is the implementation of the json.Marshaler interface.
+// It formats a single audience as a string, unless it was loaded as an array, and multiple audiences as an array.
+func (this *Audience) MarshalJSON() ([]byte, error) {
+	if len(this.Values) == 1 && !this.array {
+		return json.Marshal(this.Values[0])
+	}
+	if this.Values == nil {
+		return []byte("[]"), nil
+	}
+	return json.Marshal(this.Values)
+}
+
+// UnmarshalJSON is the implementation of the json.Unmarshaler interface.
+// It accepts a single string or an array of strings.
+func (this *Audience) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		return nil
+	}
+	var audience string
+	if err := json.Unmarshal(data, &audience); err == nil {
+		this.Values = []string{audience}
+		this.array = false
+		return nil
+	}
+	var audiences []string
+	if err := json.Unmarshal(data, &audiences); err != nil {
+		return err
+	}
+	this.Values = audiences
+	this.array = true
+	return nil
+}
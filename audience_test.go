@@ -0,0 +1,121 @@
+package gojwt_test
+
+import (
+	"encoding/json"
+	"github.com/tobyguelly/gojwt"
+	"testing"
+)
+
+func TestAudience_Json(t *testing.T) {
+	tests := []struct {
+		Input          string
+		ExpectedValues []string
+		ExpectedOutput string
+	}{
+		{
+			Input:          "{\"aud\":\"api\"}",
+			ExpectedValues: []string{"api"},
+			ExpectedOutput: "{\"aud\":\"api\"}",
+		},
+		{
+			Input:          "{\"aud\":[\"api\"]}",
+			ExpectedValues: []string{"api"},
+			ExpectedOutput: "{\"aud\":[\"api\"]}",
+		},
+		{
+			Input:          "{\"aud\":[\"api\",\"web\"]}",
+			ExpectedValues: []string{"api", "web"},
+			ExpectedOutput: "{\"aud\":[\"api\",\"web\"]}",
+		},
+		{
+			Input:          "{\"aud\":[]}",
+			ExpectedValues: []string{},
+			ExpectedOutput: "{\"aud\":[]}",
+		},
+		{
+			Input:          "{}",
+			ExpectedValues: nil,
+			ExpectedOutput: "{}",
+		},
+	}
+	for i, test := range tests {
+		var payload gojwt.Payload
+		err := json.Unmarshal([]byte(test.Input), &payload)
+		if err != nil {
+			t.Errorf("Failed test because of error: %s", err.Error())
+			t.FailNow()
+		}
+		res, err := payload.Json()
+		if err != nil {
+			t.Errorf("Failed test because of error: %s", err.Error())
+			t.FailNow()
+		}
+		var values []string
+		if payload.Audience != nil {
+			values = payload.Audience.Values
+		}
+		if res == test.ExpectedOutput && len(values) == len(test.ExpectedValues) {
+			t.Logf("Passed %d/%d tests!", i+1, len(tests))
+		} else {
+			t.Errorf("Output and expected output did not match: %s\nFound:\t\t%s %v\nExpected:\t%s %v",
+				test.Input, res, values, test.ExpectedOutput, test.ExpectedValues,
+			)
+		}
+	}
+}
+
+func TestAudience_Unmarshal(t *testing.T) {
+	tests := []string{
+		"{\"aud\":1234}",
+		"{\"aud\":[\"api\",1234]}",
+		"{\"aud\":{\"api\":true}}",
+	}
+	for i, test := range tests {
+		var payload gojwt.Payload
+		err := json.Unmarshal([]byte(test), &payload)
+		if err != nil {
+			t.Logf("Passed %d/%d tests!", i+1, len(tests))
+		} else {
+			t.Errorf("Failed test because an error was expected, but non happened: %s", test)
+		}
+	}
+}
+
+func TestAudience_Contains(t *testing.T) {
+	tests := []struct {
+		Input          *gojwt.Audience
+		Audience       string
+		ExpectedOutput bool
+	}{
+		{
+			Input:          gojwt.NewAudience("api"),
+			Audience:       "api",
+			ExpectedOutput: true,
+		},
+		{
+			Input:          gojwt.NewAudience("web", "api"),
+			Audience:       "api",
+			ExpectedOutput: true,
+		},
+		{
+			Input:          gojwt.NewAudience("web", "api"),
+			Audience:       "app",
+			ExpectedOutput: false,
+		},
+		{
+			Input:          nil,
+			Audience:       "api",
+			ExpectedOutput: false,
+		},
+	}
+	for i, test := range tests {
+		res := test.Input.Contains(test.Audience)
+		if res == test.ExpectedOutput {
+			t.Logf("Passed %d/%d tests!", i+1, len(tests))
+		} else {
+			t.Errorf("Output and expected output did not match: %s\nFound:\t\t%t\nExpected:\t%t",
+				test.Input, res, test.ExpectedOutput,
+			)
+		}
+	}
+}
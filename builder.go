@@ -1,7 +1,6 @@
 package gojwt
 
 import (
-	"crypto/rsa"
 	"time"
 )
 
@@ -18,6 +17,19 @@ func WithBuilder() *Builder {
 	}
 }
 
+// Algorithm sets the signature algorithm in the header of the JWT.
+func (this *Builder) Algorithm(alg string) *Builder {
+	this.JWT.Header.Algorithm = alg
+	return this
+}
+
+// KeyID sets the key id in the header of the JWT.
+func (this *Builder) KeyID(kid string) *Builder {
+	this.JWT.Header.KeyID = kid
+	this.JWT.autoKeyID = ""
+	return this
+}
+
 // Issuer sets the issuer property of the JWT.
 func (this *Builder) Issuer(iss string) *Builder {
 	this.JWT.Payload.Issuer = iss
@@ -31,8 +43,14 @@ func (this *Builder) Subject(sub string) *Builder {
 }
 
 // Audience sets the audience property of the JWT.
-func (this *Builder) Audience(aud string) *Builder {
-	this.JWT.Payload.Audience = aud
+// A single audience is formatted as a string, multiple audiences as an array.
+// Without audiences, the audience property is removed.
+func (this *Builder) Audience(aud ...string) *Builder {
+	if len(aud) == 0 {
+		this.JWT.Payload.Audience = nil
+		return this
+	}
+	this.JWT.Payload.Audience = NewAudience(aud...)
 	return this
 }
 
@@ -90,10 +108,20 @@ func (this *Builder) Sign(secret string) (string, error) {
 	return this.JWT.Parse()
 }
 
-// SignWithKey signs the JWT with a given label and rsa.PublicKey and returns
+// SignWithKey signs the JWT with a given private key and returns
+// the signed JWT as a string or a possible error.
+func (this *Builder) SignWithKey(key interface{}) (string, error) {
+	err := this.JWT.SignWithKey(key)
+	if err != nil {
+		return "", err
+	}
+	return this.JWT.Parse()
+}
+
+// SignWith signs the JWT with a given Signer and returns
 // the signed JWT as a string or a possible error.
-func (this *Builder) SignWithKey(label string, key rsa.PublicKey) (string, error) {
-	err := this.JWT.SignWithKey(label, key)
+func (this *Builder) SignWith(signer Signer) (string, error) {
+	err := this.JWT.SignWith(signer)
 	if err != nil {
 		return "", err
 	}
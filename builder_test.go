@@ -1,6 +1,9 @@
 package gojwt_test
 
 import (
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
 	"github.com/tobyguelly/gojwt"
 	"testing"
 	"time"
@@ -37,3 +40,207 @@ func TestBuilder(t *testing.T) {
 		}
 	}
 }
+
+func TestBuilder_SignWithKey(t *testing.T) {
+	privateKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Errorf("Failed test because of error: %s", err.Error())
+		t.FailNow()
+	}
+	tests := []struct {
+		Input         *gojwt.Builder
+		ExpectedError error
+	}{
+		{
+			Input: gojwt.WithBuilder().
+				Algorithm(gojwt.AlgRS256).
+				Subject("testSubject"),
+			ExpectedError: nil,
+		},
+		{
+			Input: gojwt.WithBuilder().
+				Algorithm(gojwt.AlgPS512).
+				Subject("testSubject"),
+			ExpectedError: nil,
+		},
+		{
+			Input: gojwt.WithBuilder().
+				Subject("testSubject"),
+			ExpectedError: gojwt.ErrInvKeyTyp,
+		},
+	}
+	for i, test := range tests {
+		token, err := test.Input.SignWithKey(privateKey)
+		if err == nil {
+			var jwt *gojwt.JWT
+			jwt, err = gojwt.LoadJWT(token)
+			if err == nil {
+				err = jwt.ValidateWithKey(&privateKey.PublicKey)
+			}
+		}
+		if err == test.ExpectedError {
+			t.Logf("Passed %d/%d tests!", i+1, len(tests))
+		} else {
+			t.Errorf("Output and expected output did not match: %s\nFound:\t\t%v\nExpected:\t%v",
+				token, err, test.ExpectedError,
+			)
+		}
+	}
+}
+
+func TestBuilder_Audience(t *testing.T) {
+	tests := []struct {
+		Input          *gojwt.Builder
+		ExpectedOutput string
+	}{
+		{
+			Input:          gojwt.WithBuilder().Issuer("").Audience("api"),
+			ExpectedOutput: "{\"aud\":\"api\"}",
+		},
+		{
+			Input:          gojwt.WithBuilder().Issuer("").Audience("api", "web"),
+			ExpectedOutput: "{\"aud\":[\"api\",\"web\"]}",
+		},
+		{
+			Input:          gojwt.WithBuilder().Issuer("").Audience(),
+			ExpectedOutput: "{}",
+		},
+		{
+			Input:          gojwt.WithBuilder().Issuer("").Audience("api").Audience(),
+			ExpectedOutput: "{}",
+		},
+	}
+	for i, test := range tests {
+		token, err := test.Input.Sign("secret")
+		if err != nil {
+			t.Errorf("Failed test because of error: %s", err.Error())
+			t.FailNow()
+		}
+		jwt, err := gojwt.LoadJWT(token)
+		if err != nil {
+			t.Errorf("Failed test because of error: %s", err.Error())
+			t.FailNow()
+		}
+		res, _ := jwt.Payload.Json()
+		valid := gojwt.NewValidator(gojwt.ExpectAudience("api")).Validate(jwt, "secret") == nil
+		if res == test.ExpectedOutput && valid == (jwt.Payload.Audience != nil) {
+			t.Logf("Passed %d/%d tests!", i+1, len(tests))
+		} else {
+			t.Errorf("Output and expected output did not match: %s\nFound:\t\t%s\nExpected:\t%s",
+				token, res, test.ExpectedOutput,
+			)
+		}
+	}
+}
+
+func TestBuilder_KeyID(t *testing.T) {
+	privateKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Errorf("Failed test because of error: %s", err.Error())
+		t.FailNow()
+	}
+	thumbprint, err := (&gojwt.JWK{Key: &privateKey.PublicKey}).Thumbprint(crypto.SHA256)
+	if err != nil {
+		t.Errorf("Failed test because of error: %s", err.Error())
+		t.FailNow()
+	}
+	otherKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Errorf("Failed test because of error: %s", err.Error())
+		t.FailNow()
+	}
+	tests := []struct {
+		Input          *gojwt.Builder
+		Previous       interface{}
+		Key            interface{}
+		ExpectedOutput string
+	}{
+		{
+			Input:          gojwt.WithBuilder().Algorithm(gojwt.AlgRS256),
+			Key:            privateKey,
+			ExpectedOutput: thumbprint,
+		},
+		{
+			Input:          gojwt.WithBuilder().Algorithm(gojwt.AlgRS256),
+			Previous:       otherKey,
+			Key:            privateKey,
+			ExpectedOutput: thumbprint,
+		},
+		{
+			Input:          gojwt.WithBuilder().Algorithm(gojwt.AlgRS256).KeyID("custom"),
+			Previous:       otherKey,
+			Key:            privateKey,
+			ExpectedOutput: "custom",
+		},
+		{
+			Input:          gojwt.WithBuilder().Algorithm(gojwt.AlgRS256),
+			Key:            gojwt.BindKey(privateKey, gojwt.AlgRS256),
+			ExpectedOutput: thumbprint,
+		},
+		{
+			Input:          gojwt.WithBuilder().Algorithm(gojwt.AlgRS256).KeyID("custom"),
+			Key:            privateKey,
+			ExpectedOutput: "custom",
+		},
+		{
+			Input:          gojwt.WithBuilder().Algorithm(gojwt.AlgRS256),
+			Key:            &gojwt.JWK{Key: privateKey, KeyID: "jwk"},
+			ExpectedOutput: "jwk",
+		},
+		{
+			Input:          gojwt.WithBuilder().Algorithm(gojwt.AlgHS256),
+			Key:            "secret",
+			ExpectedOutput: "",
+		},
+		{
+			Input:          gojwt.WithBuilder().Algorithm(gojwt.AlgHS256),
+			Key:            &gojwt.JWK{Key: []byte("secret")},
+			ExpectedOutput: "",
+		},
+	}
+	for i, test := range tests {
+		if test.Previous != nil {
+			_, err = test.Input.SignWithKey(test.Previous)
+			if err != nil {
+				t.Errorf("Failed test because of error: %s", err.Error())
+				continue
+			}
+		}
+		token, err := test.Input.SignWithKey(test.Key)
+		if err != nil {
+			t.Errorf("Failed test because of error: %s", err.Error())
+			continue
+		}
+		jwt, err := gojwt.LoadJWT(token)
+		if err != nil {
+			t.Errorf("Failed test because of error: %s", err.Error())
+			continue
+		}
+		if jwt.Header.KeyID == test.ExpectedOutput {
+			t.Logf("Passed %d/%d tests!", i+1, len(tests))
+		} else {
+			t.Errorf("Output and expected output did not match: %s\nFound:\t\t%s\nExpected:\t%s",
+				token, jwt.Header.KeyID, test.ExpectedOutput,
+			)
+		}
+	}
+	token, err := gojwt.WithBuilder().Algorithm(gojwt.AlgRS256).SignWithKey(otherKey)
+	if err != nil {
+		t.Errorf("Failed test because of error: %s", err.Error())
+		t.FailNow()
+	}
+	loaded, err := gojwt.LoadJWT(token)
+	if err != nil {
+		t.Errorf("Failed test because of error: %s", err.Error())
+		t.FailNow()
+	}
+	err = loaded.SignWithKey(privateKey)
+	if err == nil {
+		err = loaded.ValidateWithKeySet(&gojwt.JWKSet{Keys: []*gojwt.JWK{{Key: &privateKey.PublicKey}}})
+	}
+	if err != nil || loaded.Header.KeyID != thumbprint {
+		t.Errorf("Output and expected output did not match: %s\nFound:\t\t%s %v\nExpected:\t%s",
+			token, loaded.Header.KeyID, err, thumbprint,
+		)
+	}
+}
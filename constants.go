@@ -6,21 +6,118 @@ var (
 	// ErrAlgNotImp indicates that the algorithm in the JWT header is not implemented for the signing/validating method.
 	ErrAlgNotImp = errors.New("SIGNATURE ALGORITHM NOT IMPLEMENTED FOR METHOD")
 
+	// ErrAlgNotAll indicates that the algorithm in the JWT header is not allowed for the key or the validation,
+	// which prevents tokens from choosing the algorithm they are verified with.
+	ErrAlgNotAll = errors.New("SIGNATURE ALGORITHM NOT ALLOWED")
+
 	// ErrTokNotSig indicates that the JWT has not been signed yet, and therefore can't be validated.
 	ErrTokNotSig = errors.New("TOKEN NOT SIGNED / MISSING SIGNATURE")
 
 	// ErrInvSecKey indicates that the JWT has failed a validation, because of an invalid secret key.
 	ErrInvSecKey = errors.New("INVALID SECRET")
 
+	// ErrInvKeyTyp indicates that the type of the given key can not be used with the algorithm in the JWT header.
+	ErrInvKeyTyp = errors.New("INVALID KEY TYPE FOR ALGORITHM")
+
+	// ErrInvKeyCrv indicates that the elliptic curve of the given key does not match the algorithm in the JWT header.
+	ErrInvKeyCrv = errors.New("INVALID KEY CURVE FOR ALGORITHM")
+
+	// ErrInvJWK indicates that a JSON Web Key is malformed or its members are inconsistent.
+	ErrInvJWK = errors.New("INVALID / MALFORMED JWK")
+
+	// ErrInvKeyUse indicates that the use or key_ops members of a JSON Web Key do not permit the operation.
+	ErrInvKeyUse = errors.New("KEY USE NOT PERMITTED FOR OPERATION")
+
+	// ErrKeyNotFnd indicates that no key of a key set matches the kid (KeyID) header and the algorithm of a JWT.
+	ErrKeyNotFnd = errors.New("NO MATCHING KEY FOUND")
+
+	// ErrKeySetFtch indicates that a remote key set could not be fetched or its response is not a valid key set.
+	ErrKeySetFtch = errors.New("FAILED TO FETCH KEY SET")
+
+	// ErrInvPEM indicates that a PEM or DER encoded blob holds no key or a key of an unsupported type.
+	ErrInvPEM = errors.New("INVALID / UNSUPPORTED PEM OR DER KEY")
+
+	// ErrEncKey indicates that a PEM encoded key is encrypted, which has to be decrypted before loading.
+	ErrEncKey = errors.New("ENCRYPTED KEYS NOT SUPPORTED")
+
 	// ErrBadJWTTok indicates that a given string is not a valid JWT token.
 	ErrBadJWTTok = errors.New("NOT A JWT / BAD JWT")
 
+	// ErrBadJWETok indicates that a given string is not a valid JWE token.
+	ErrBadJWETok = errors.New("NOT A JWE / BAD JWE")
+
+	// ErrBadJWSTok indicates that a given string is not a valid JWS in the compact or JSON serialization.
+	ErrBadJWSTok = errors.New("NOT A JWS / BAD JWS")
+
+	// ErrInvCrit indicates that the crit (Critical) header of a JWS or a JWE is malformed or lists a header, which is not supported.
+	ErrInvCrit = errors.New("INVALID / UNSUPPORTED CRITICAL HEADER")
+
+	// ErrInvSigs indicates that the valid signatures of a JWS do not meet the SignatureRequirement.
+	ErrInvSigs = errors.New("REQUIRED SIGNATURES NOT VALID")
+
+	// ErrInvTokCty indicates that the cty (ContentType) header of a JWE is not "JWT", so it is not a nested JWT.
+	ErrInvTokCty = errors.New("INVALID TOKEN CONTENT TYPE")
+
+	// ErrTokNotEnc indicates that the JWE has not been encrypted yet, and therefore can't be decrypted or parsed.
+	ErrTokNotEnc = errors.New("TOKEN NOT ENCRYPTED")
+
+	// ErrDecFail indicates that a JWE could not be decrypted, because the key does not match or the token was modified.
+	ErrDecFail = errors.New("DECRYPTION FAILED")
+
 	// ErrInvTokPrd indicates that a given JWT has failed a validation.
 	// This happened because of either the nbf (NotBefore) or exp (ExpirationTime) claim had invalid dates.
 	ErrInvTokPrd = errors.New("TOKEN VALIDITY PERIOD EXPIRED OR NOT STARTED")
 
+	// ErrInvTokIss indicates that the iss (Issuer) claim of a JWT is not one of the expected issuers.
+	ErrInvTokIss = errors.New("INVALID TOKEN ISSUER")
+
+	// ErrInvTokAud indicates that the aud (Audience) claim of a JWT does not contain the expected audience.
+	ErrInvTokAud = errors.New("INVALID TOKEN AUDIENCE")
+
+	// ErrInvTokIat indicates that the iat (IssuedAt) claim of a JWT lies in the future
+	// or the token is older than the maximum token age.
+	ErrInvTokIat = errors.New("TOKEN ISSUED IN FUTURE OR TOO OLD")
+
+	// ErrInvTokTyp indicates that the typ (Type) header of a JWT is not one of the allowed types.
+	ErrInvTokTyp = errors.New("INVALID TOKEN TYPE")
+
+	// ErrMisTokClm indicates that a JWT does not contain a required claim.
+	ErrMisTokClm = errors.New("MISSING REQUIRED CLAIM")
+
+	// ErrTokExpd indicates that a JWT has failed a validation, because its exp (ExpirationTime) claim has passed.
+	// Validation errors of this kind also match ErrInvTokPrd.
+	ErrTokExpd = errors.New("TOKEN EXPIRED")
+
+	// ErrTokNotAct indicates that a JWT has failed a validation, because its nbf (NotBefore) claim has not passed yet.
+	// Validation errors of this kind also match ErrInvTokPrd.
+	ErrTokNotAct = errors.New("TOKEN NOT VALID YET")
+
+	// ErrTokRevk indicates that a JWT has failed a validation, because it has been revoked in a RevocationStore.
+	ErrTokRevk = errors.New("TOKEN REVOKED")
+
+	// ErrTokIssCut indicates that a JWT has failed a validation, because it has been issued before the cutoff
+	// of its subject in a CutoffStore.
+	ErrTokIssCut = errors.New("TOKEN ISSUED BEFORE SUBJECT CUTOFF")
+
+	// ErrStoreUnav indicates that a RevocationStore or a CutoffStore failed, so the validity of a JWT is unknown.
+	// Errors of this kind also match the error of the store.
+	ErrStoreUnav = errors.New("TOKEN STORE UNAVAILABLE")
+
+	// ErrRefReuse indicates that a refresh token has been used again after it has been rotated,
+	// which revokes its token family.
+	ErrRefReuse = errors.New("REFRESH TOKEN REUSED")
+
 	// ErrPayFieldVal indicates that a given payload has failed field format validation.
 	ErrPayFieldVal = errors.New("ONE OR MORE FIELDS PRODUCE A VALIDATION ERROR")
+
+	// ErrTokMis indicates that an HTTP request does not contain a token.
+	ErrTokMis = errors.New("TOKEN MISSING IN REQUEST")
+
+	// ErrBadReq indicates that an HTTP request contains a malformed token, like an empty token or multiple tokens.
+	ErrBadReq = errors.New("MALFORMED TOKEN REQUEST")
+
+	// ErrInsScope indicates that a JWT does not grant the scopes required for accessing a resource.
+	ErrInsScope = errors.New("INSUFFICIENT SCOPE")
 )
 
 var (
@@ -55,17 +152,121 @@ const (
 	// AlgHS512 indicates that the JWT uses the HS512 algorithm for signing the signature.
 	AlgHS512 = "HS512"
 
-	// AlgRS256 indicates that the JWT uses the RS256 algorithm for encrypting and decrypting the signature.
+	// AlgRS256 indicates that the JWT uses the RS256 algorithm (RSASSA-PKCS1-v1_5 using SHA-256) for signing the signature.
 	AlgRS256 = "RS256"
 
-	// AlgRS384 indicates that the JWT uses the RS384 algorithm for encrypting and decrypting the signature.
+	// AlgRS384 indicates that the JWT uses the RS384 algorithm (RSASSA-PKCS1-v1_5 using SHA-384) for signing the signature.
 	AlgRS384 = "RS384"
 
-	// AlgRS512 indicates that the JWT uses the RS512 algorithm for encrypting and decrypting the signature.
+	// AlgRS512 indicates that the JWT uses the RS512 algorithm (RSASSA-PKCS1-v1_5 using SHA-512) for signing the signature.
 	AlgRS512 = "RS512"
+
+	// AlgPS256 indicates that the JWT uses the PS256 algorithm (RSASSA-PSS using SHA-256) for signing the signature.
+	AlgPS256 = "PS256"
+
+	// AlgPS384 indicates that the JWT uses the PS384 algorithm (RSASSA-PSS using SHA-384) for signing the signature.
+	AlgPS384 = "PS384"
+
+	// AlgPS512 indicates that the JWT uses the PS512 algorithm (RSASSA-PSS using SHA-512) for signing the signature.
+	AlgPS512 = "PS512"
+
+	// AlgES256 indicates that the JWT uses the ES256 algorithm (ECDSA using P-256 and SHA-256) for signing the signature.
+	AlgES256 = "ES256"
+
+	// AlgES384 indicates that the JWT uses the ES384 algorithm (ECDSA using P-384 and SHA-384) for signing the signature.
+	AlgES384 = "ES384"
+
+	// AlgES512 indicates that the JWT uses the ES512 algorithm (ECDSA using P-521 and SHA-512) for signing the signature.
+	AlgES512 = "ES512"
+
+	// AlgEdDSA indicates that the JWT uses the EdDSA algorithm (Ed25519 as specified in RFC 8037) for signing the signature.
+	AlgEdDSA = "EdDSA"
+)
+
+const (
+	// AlgRSAOAEP indicates that the JWE uses the RSA-OAEP algorithm (RSAES OAEP using SHA-1) for encrypting the key.
+	AlgRSAOAEP = "RSA-OAEP"
+
+	// AlgRSAOAEP256 indicates that the JWE uses the RSA-OAEP-256 algorithm (RSAES OAEP using SHA-256) for encrypting the key.
+	AlgRSAOAEP256 = "RSA-OAEP-256"
+
+	// AlgA128KW indicates that the JWE uses the A128KW algorithm (AES Key Wrap using a 128-bit key) for encrypting the key.
+	AlgA128KW = "A128KW"
+
+	// AlgA256KW indicates that the JWE uses the A256KW algorithm (AES Key Wrap using a 256-bit key) for encrypting the key.
+	AlgA256KW = "A256KW"
+
+	// AlgDir indicates that the JWE uses the shared symmetric key directly as the content encryption key.
+	AlgDir = "dir"
+
+	// AlgECDHES indicates that the JWE uses the content encryption key agreed with ECDH-ES (Elliptic Curve Diffie-Hellman
+	// Ephemeral Static) and the Concat KDF directly.
+	AlgECDHES = "ECDH-ES"
+
+	// AlgECDHESA128KW indicates that the JWE uses a key agreed with ECDH-ES for wrapping the key with A128KW.
+	AlgECDHESA128KW = "ECDH-ES+A128KW"
+
+	// AlgECDHESA256KW indicates that the JWE uses a key agreed with ECDH-ES for wrapping the key with A256KW.
+	AlgECDHESA256KW = "ECDH-ES+A256KW"
+)
+
+const (
+	// EncA128GCM indicates that the JWE uses AES GCM using a 128-bit key for encrypting the content.
+	EncA128GCM = "A128GCM"
+
+	// EncA256GCM indicates that the JWE uses AES GCM using a 256-bit key for encrypting the content.
+	EncA256GCM = "A256GCM"
+
+	// EncA128CBCHS256 indicates that the JWE uses AES CBC using a 128-bit key with HMAC SHA-256 for encrypting the content.
+	EncA128CBCHS256 = "A128CBC-HS256"
 )
 
 const (
 	// TypJWT indicates that the token type is JWT.
 	TypJWT = "JWT"
+
+	// TypAccessJWT indicates that the token is an access token as specified in RFC 9068.
+	TypAccessJWT = "at+jwt"
+
+	// TypRefreshJWT indicates that the token is a refresh token issued by a TokenPairIssuer.
+	TypRefreshJWT = "refresh+jwt"
+)
+
+const (
+	// KtyRSA indicates that a JSON Web Key is an RSA key.
+	KtyRSA = "RSA"
+
+	// KtyEC indicates that a JSON Web Key is an elliptic curve key.
+	KtyEC = "EC"
+
+	// KtyOKP indicates that a JSON Web Key is an octet key pair, like Ed25519 and X25519 keys as specified in RFC 8037.
+	KtyOKP = "OKP"
+
+	// KtyOct indicates that a JSON Web Key is a symmetric octet sequence.
+	KtyOct = "oct"
+)
+
+const (
+	// UseSig indicates that a JSON Web Key is used for signatures.
+	UseSig = "sig"
+
+	// UseEnc indicates that a JSON Web Key is used for encryption.
+	UseEnc = "enc"
+)
+
+const (
+	// CrvP256 indicates that an elliptic curve key uses the P-256 curve.
+	CrvP256 = "P-256"
+
+	// CrvP384 indicates that an elliptic curve key uses the P-384 curve.
+	CrvP384 = "P-384"
+
+	// CrvP521 indicates that an elliptic curve key uses the P-521 curve.
+	CrvP521 = "P-521"
+
+	// CrvEd25519 indicates that an octet key pair is an Ed25519 key.
+	CrvEd25519 = "Ed25519"
+
+	// CrvX25519 indicates that an octet key pair is an X25519 key as specified in RFC 8037.
+	CrvX25519 = "X25519"
 )
@@ -0,0 +1,86 @@
+package gojwt
+
+import (
+	"sync"
+	"time"
+)
+
+// CutoffStore stores a cutoff time per subject, to invalidate all tokens of a subject issued before the cutoff,
+// like after a password change. Implementations must be safe for concurrent use.
+type CutoffStore interface {
+
+	// SetCutoff sets the cutoff time of a subject. Tokens of the subject issued before the cutoff are rejected.
+	SetCutoff(subject string, cutoff time.Time) error
+
+	// Cutoff returns the cutoff time of a subject and a bool, whether a cutoff has been set for the subject.
+	Cutoff(subject string) (time.Time, bool, error)
+}
+
+// CheckCutoffs sets a CutoffStore, which is queried for tokens with a sub (Subject) claim.
+// Tokens issued before the cutoff of their subject are rejected with ErrTokIssCut. Because the iat (IssuedAt) claim
+// only holds whole seconds, a cutoff within a second is rounded up, so tokens issued in the same second are rejected.
+// Tokens without the iat claim are rejected with ErrTokIssCut if their subject has a cutoff and rejectMissingIat
+// is set, otherwise they are accepted.
+// Errors of the CutoffStore are returned as ErrStoreUnav, which also matches the error of the store.
+func CheckCutoffs(store CutoffStore, rejectMissingIat bool) ValidatorOption {
+	return func(validator *Validator) {
+		validator.cutoffs = store
+		validator.rejectMissingIat = rejectMissingIat
+	}
+}
+
+// MemoryCutoffStore is a CutoffStore, which keeps the cutoff times in memory.
+type MemoryCutoffStore struct {
+	mutex   sync.RWMutex
+	cutoffs map[string]time.Time
+}
+
+// NewMemoryCutoffStore creates a new and empty MemoryCutoffStore.
+func NewMemoryCutoffStore() *MemoryCutoffStore {
+	return &MemoryCutoffStore{
+		cutoffs: make(map[string]time.Time),
+	}
+}
+
+// SetCutoff is the implementation of the CutoffStore interface.
+// A cutoff is never moved backwards, so cutoffs before the current cutoff of a subject are ignored.
+func (this *MemoryCutoffStore) SetCutoff(subject string, cutoff time.Time) error {
+	this.mutex.Lock()
+	defer this.mutex.Unlock()
+	if current, ok := this.cutoffs[subject]; !ok || cutoff.After(current) {
+		this.cutoffs[subject] = cutoff
+	}
+	return nil
+}
+
+// Cutoff is the implementation of the CutoffStore interface.
+func (this *MemoryCutoffStore) Cutoff(subject string) (time.Time, bool, error) {
+	this.mutex.RLock()
+	defer this.mutex.RUnlock()
+	cutoff, ok := this.cutoffs[subject]
+	return cutoff, ok, nil
+}
+
+// checkCutoff returns a ValidationError if a payload has been issued before the cutoff of its subject.
+func checkCutoff(store CutoffStore, payload *Payload, rejectMissingIat bool) (*ValidationError, error) {
+	if payload.Subject == "" {
+		return nil, nil
+	}
+	cutoff, ok, err := store.Cutoff(payload.Subject)
+	if err != nil || !ok {
+		return nil, err
+	}
+	if truncated := cutoff.Truncate(time.Second); truncated.Before(cutoff) {
+		cutoff = truncated.Add(time.Second)
+	}
+	if payload.IssuedAt == nil {
+		if rejectMissingIat {
+			return &ValidationError{Err: ErrTokIssCut, Claim: "iat", Expected: cutoff}, nil
+		}
+		return nil, nil
+	}
+	if payload.IssuedAt.Time.Before(cutoff) {
+		return &ValidationError{Err: ErrTokIssCut, Claim: "iat", Value: payload.IssuedAt.Time, Expected: cutoff}, nil
+	}
+	return nil, nil
+}
@@ -0,0 +1,160 @@
+package gojwt_test
+
+import (
+	"errors"
+	"github.com/tobyguelly/gojwt"
+	"testing"
+	"time"
+)
+
+type unavailableCutoffStore struct{}
+
+func (this unavailableCutoffStore) SetCutoff(subject string, cutoff time.Time) error {
+	return errStoreUnavailable
+}
+
+func (this unavailableCutoffStore) Cutoff(subject string) (time.Time, bool, error) {
+	return time.Time{}, false, errStoreUnavailable
+}
+
+func TestMemoryCutoffStore(t *testing.T) {
+	cutoff := time.Unix(1700000000, 0)
+	store := gojwt.NewMemoryCutoffStore()
+	cutoffs := []struct {
+		Subject string
+		Cutoff  time.Time
+	}{
+		{Subject: "Joe", Cutoff: cutoff},
+		{Subject: "Joe", Cutoff: cutoff.Add(-time.Hour)},
+		{Subject: "Jane", Cutoff: cutoff.Add(-time.Hour)},
+		{Subject: "Jane", Cutoff: cutoff},
+	}
+	for _, test := range cutoffs {
+		err := store.SetCutoff(test.Subject, test.Cutoff)
+		if err != nil {
+			t.Errorf("Failed test because of error: %s", err.Error())
+			t.FailNow()
+		}
+	}
+	tests := []struct {
+		Subject        string
+		Expected       time.Time
+		ExpectedExists bool
+	}{
+		{Subject: "Joe", Expected: cutoff, ExpectedExists: true},
+		{Subject: "Jane", Expected: cutoff, ExpectedExists: true},
+		{Subject: "John", ExpectedExists: false},
+	}
+	for i, test := range tests {
+		res, ok, err := store.Cutoff(test.Subject)
+		if err == nil && res.Equal(test.Expected) && ok == test.ExpectedExists {
+			t.Logf("Passed %d/%d tests!", i+1, len(tests))
+		} else {
+			t.Errorf("Output and expected output did not match: %s\nFound:\t\t%v %v %v\nExpected:\t%v %v",
+				test.Subject, res, ok, err, test.Expected, test.ExpectedExists,
+			)
+		}
+	}
+}
+
+func TestValidator_CheckCutoffs(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	store := gojwt.NewMemoryCutoffStore()
+	err := store.SetCutoff("Joe", now.Add(900*time.Millisecond))
+	if err != nil {
+		t.Errorf("Failed test because of error: %s", err.Error())
+		t.FailNow()
+	}
+	err = store.SetCutoff("Jane", now)
+	if err != nil {
+		t.Errorf("Failed test because of error: %s", err.Error())
+		t.FailNow()
+	}
+	token := func(subject string, issuedAt *gojwt.Time) gojwt.JWT {
+		jwt := gojwt.NewJWT()
+		jwt.Payload.Subject = subject
+		jwt.Payload.IssuedAt = issuedAt
+		return jwt
+	}
+	tests := []struct {
+		Input            gojwt.JWT
+		Store            gojwt.CutoffStore
+		RejectMissingIat bool
+		ExpectedError    error
+	}{
+		{
+			Input:         token("Joe", gojwt.Wrap(now.Add(-time.Hour))),
+			Store:         store,
+			ExpectedError: gojwt.ErrTokIssCut,
+		},
+		{
+			Input:         token("Joe", gojwt.Wrap(now.Add(100*time.Millisecond))),
+			Store:         store,
+			ExpectedError: gojwt.ErrTokIssCut,
+		},
+		{
+			Input:         token("Joe", gojwt.Wrap(now.Add(time.Second))),
+			Store:         store,
+			ExpectedError: nil,
+		},
+		{
+			Input:         token("Jane", gojwt.Wrap(now.Add(-time.Second))),
+			Store:         store,
+			ExpectedError: gojwt.ErrTokIssCut,
+		},
+		{
+			Input:         token("Jane", gojwt.Wrap(now)),
+			Store:         store,
+			ExpectedError: nil,
+		},
+		{
+			Input:         token("John", gojwt.Wrap(now.Add(-time.Hour))),
+			Store:         store,
+			ExpectedError: nil,
+		},
+		{
+			Input:            token("Joe", nil),
+			Store:            store,
+			RejectMissingIat: true,
+			ExpectedError:    gojwt.ErrTokIssCut,
+		},
+		{
+			Input:            token("Joe", nil),
+			Store:            store,
+			RejectMissingIat: false,
+			ExpectedError:    nil,
+		},
+		{
+			Input:            token("John", nil),
+			Store:            store,
+			RejectMissingIat: true,
+			ExpectedError:    nil,
+		},
+		{
+			Input:         token("", gojwt.Wrap(now.Add(-time.Hour))),
+			Store:         unavailableCutoffStore{},
+			ExpectedError: nil,
+		},
+		{
+			Input:         token("Joe", gojwt.Wrap(now.Add(-time.Hour))),
+			Store:         unavailableCutoffStore{},
+			ExpectedError: errStoreUnavailable,
+		},
+	}
+	for i, test := range tests {
+		token, err := test.Input.SignParse("secret")
+		if err == nil {
+			_, err = gojwt.NewValidator(
+				gojwt.CheckCutoffs(test.Store, test.RejectMissingIat),
+				gojwt.UseClock(func() time.Time { return now.Add(time.Minute) }),
+			).Parse(token, "secret")
+		}
+		if errors.Is(err, test.ExpectedError) {
+			t.Logf("Passed %d/%d tests!", i+1, len(tests))
+		} else {
+			t.Errorf("Output and expected output did not match: %s %v\nFound:\t\t%v\nExpected:\t%v",
+				test.Input.Payload.Subject, test.Input.Payload.IssuedAt, err, test.ExpectedError,
+			)
+		}
+	}
+}
@@ -1,6 +1,10 @@
 package gojwt
 
 import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
 	"crypto/hmac"
 	"crypto/rand"
 	"crypto/rsa"
@@ -8,26 +12,11 @@ import (
 	"crypto/sha512"
 	"encoding/base64"
 	"hash"
-)
-
-type (
-	AlgorithmMap           map[string]func(message, secret string) (string, error)
-	EncryptionAlgorithmMap map[string]func(message string, label []byte, key rsa.PublicKey) (string, error)
-	DecryptionAlgorithmMap map[string]func(message string, label []byte, key rsa.PrivateKey) (string, error)
+	"math/big"
 )
 
 var (
-	Algorithms = AlgorithmMap{
-		AlgHS256: SignHS256,
-		AlgHS384: SignHS384,
-		AlgHS512: SignHS512,
-	}
-	EncryptionAlgorithms = EncryptionAlgorithmMap{
-		AlgRS256: EncryptRS256,
-		AlgRS384: EncryptRS384,
-		AlgRS512: EncryptRS512,
-	}
-	DecryptionAlgorithms = DecryptionAlgorithmMap{
+	legacyDecryptionAlgorithms = map[string]func(message string, label []byte, key rsa.PrivateKey) (string, error){
 		AlgRS256: DecryptRS256,
 		AlgRS384: DecryptRS384,
 		AlgRS512: DecryptRS512,
@@ -72,6 +61,267 @@ func SignHS512(message, secret string) (string, error) {
 	return signHS(sha512.New, message, secret)
 }
 
+func signRS(hash crypto.Hash, message string, privateKey *rsa.PrivateKey) (string, error) {
+	h := hash.New()
+	_, err := h.Write([]byte(message))
+	if err != nil {
+		return "", err
+	}
+	signature, err := rsa.SignPKCS1v15(rand.Reader, privateKey, hash, h.Sum(nil))
+	if err != nil {
+		return "", err
+	}
+	return EncodeBase64(string(signature)), nil
+}
+
+func verifyRS(hash crypto.Hash, message, encodedSignature string, publicKey *rsa.PublicKey) error {
+	signature, err := DecodeBase64(encodedSignature)
+	if err != nil {
+		return ErrInvSecKey
+	}
+	h := hash.New()
+	_, err = h.Write([]byte(message))
+	if err != nil {
+		return err
+	}
+	if rsa.VerifyPKCS1v15(publicKey, hash, h.Sum(nil), signature) != nil {
+		return ErrInvSecKey
+	}
+	return nil
+}
+
+// SignRS256 signs a message string with an RSA private key using the RS256 algorithm (RSASSA-PKCS1-v1_5 with SHA-256)
+// with additional base64 rawURLEncoding of the result signature.
+func SignRS256(message string, privateKey *rsa.PrivateKey) (string, error) {
+	return signRS(crypto.SHA256, message, privateKey)
+}
+
+// VerifyRS256 verifies a base64 rawURLEncoded signature of a message string with an RSA public key
+// using the RS256 algorithm (RSASSA-PKCS1-v1_5 with SHA-256).
+// Returns ErrInvSecKey if the signature does not match.
+func VerifyRS256(message, signature string, publicKey *rsa.PublicKey) error {
+	return verifyRS(crypto.SHA256, message, signature, publicKey)
+}
+
+// SignRS384 signs a message string with an RSA private key using the RS384 algorithm (RSASSA-PKCS1-v1_5 with SHA-384)
+// with additional base64 rawURLEncoding of the result signature.
+func SignRS384(message string, privateKey *rsa.PrivateKey) (string, error) {
+	return signRS(crypto.SHA384, message, privateKey)
+}
+
+// VerifyRS384 verifies a base64 rawURLEncoded signature of a message string with an RSA public key
+// using the RS384 algorithm (RSASSA-PKCS1-v1_5 with SHA-384).
+// Returns ErrInvSecKey if the signature does not match.
+func VerifyRS384(message, signature string, publicKey *rsa.PublicKey) error {
+	return verifyRS(crypto.SHA384, message, signature, publicKey)
+}
+
+// SignRS512 signs a message string with an RSA private key using the RS512 algorithm (RSASSA-PKCS1-v1_5 with SHA-512)
+// with additional base64 rawURLEncoding of the result signature.
+func SignRS512(message string, privateKey *rsa.PrivateKey) (string, error) {
+	return signRS(crypto.SHA512, message, privateKey)
+}
+
+// VerifyRS512 verifies a base64 rawURLEncoded signature of a message string with an RSA public key
+// using the RS512 algorithm (RSASSA-PKCS1-v1_5 with SHA-512).
+// Returns ErrInvSecKey if the signature does not match.
+func VerifyRS512(message, signature string, publicKey *rsa.PublicKey) error {
+	return verifyRS(crypto.SHA512, message, signature, publicKey)
+}
+
+// The salt length of RSASSA-PSS signatures must equal the size of the hash output, as specified in RFC 7518.
+func signPS(hash crypto.Hash, message string, privateKey *rsa.PrivateKey) (string, error) {
+	h := hash.New()
+	_, err := h.Write([]byte(message))
+	if err != nil {
+		return "", err
+	}
+	signature, err := rsa.SignPSS(rand.Reader, privateKey, hash, h.Sum(nil), &rsa.PSSOptions{
+		SaltLength: rsa.PSSSaltLengthEqualsHash,
+		Hash:       hash,
+	})
+	if err != nil {
+		return "", err
+	}
+	return EncodeBase64(string(signature)), nil
+}
+
+func verifyPS(hash crypto.Hash, message, encodedSignature string, publicKey *rsa.PublicKey) error {
+	signature, err := DecodeBase64(encodedSignature)
+	if err != nil {
+		return ErrInvSecKey
+	}
+	h := hash.New()
+	_, err = h.Write([]byte(message))
+	if err != nil {
+		return err
+	}
+	err = rsa.VerifyPSS(publicKey, hash, h.Sum(nil), signature, &rsa.PSSOptions{
+		SaltLength: rsa.PSSSaltLengthEqualsHash,
+		Hash:       hash,
+	})
+	if err != nil {
+		return ErrInvSecKey
+	}
+	return nil
+}
+
+// SignPS256 signs a message string with an RSA private key using the PS256 algorithm (RSASSA-PSS with SHA-256)
+// with additional base64 rawURLEncoding of the result signature.
+func SignPS256(message string, privateKey *rsa.PrivateKey) (string, error) {
+	return signPS(crypto.SHA256, message, privateKey)
+}
+
+// VerifyPS256 verifies a base64 rawURLEncoded signature of a message string with an RSA public key
+// using the PS256 algorithm (RSASSA-PSS with SHA-256).
+// Returns ErrInvSecKey if the signature does not match.
+func VerifyPS256(message, signature string, publicKey *rsa.PublicKey) error {
+	return verifyPS(crypto.SHA256, message, signature, publicKey)
+}
+
+// SignPS384 signs a message string with an RSA private key using the PS384 algorithm (RSASSA-PSS with SHA-384)
+// with additional base64 rawURLEncoding of the result signature.
+func SignPS384(message string, privateKey *rsa.PrivateKey) (string, error) {
+	return signPS(crypto.SHA384, message, privateKey)
+}
+
+// VerifyPS384 verifies a base64 rawURLEncoded signature of a message string with an RSA public key
+// using the PS384 algorithm (RSASSA-PSS with SHA-384).
+// Returns ErrInvSecKey if the signature does not match.
+func VerifyPS384(message, signature string, publicKey *rsa.PublicKey) error {
+	return verifyPS(crypto.SHA384, message, signature, publicKey)
+}
+
+// SignPS512 signs a message string with an RSA private key using the PS512 algorithm (RSASSA-PSS with SHA-512)
+// with additional base64 rawURLEncoding of the result signature.
+func SignPS512(message string, privateKey *rsa.PrivateKey) (string, error) {
+	return signPS(crypto.SHA512, message, privateKey)
+}
+
+// VerifyPS512 verifies a base64 rawURLEncoded signature of a message string with an RSA public key
+// using the PS512 algorithm (RSASSA-PSS with SHA-512).
+// Returns ErrInvSecKey if the signature does not match.
+func VerifyPS512(message, signature string, publicKey *rsa.PublicKey) error {
+	return verifyPS(crypto.SHA512, message, signature, publicKey)
+}
+
+// ECDSA signatures are encoded as the fixed width concatenation of R and S, as specified in RFC 7518,
+// instead of the ASN.1 DER encoding used by crypto/ecdsa.
+func signES(hash crypto.Hash, curve elliptic.Curve, message string, privateKey *ecdsa.PrivateKey) (string, error) {
+	if privateKey.Curve == nil || privateKey.Curve.Params().Name != curve.Params().Name {
+		return "", ErrInvKeyCrv
+	}
+	h := hash.New()
+	_, err := h.Write([]byte(message))
+	if err != nil {
+		return "", err
+	}
+	r, s, err := ecdsa.Sign(rand.Reader, privateKey, h.Sum(nil))
+	if err != nil {
+		return "", err
+	}
+	size := (curve.Params().BitSize + 7) / 8
+	signature := make([]byte, 2*size)
+	r.FillBytes(signature[:size])
+	s.FillBytes(signature[size:])
+	return EncodeBase64(string(signature)), nil
+}
+
+func verifyES(hash crypto.Hash, curve elliptic.Curve, message, encodedSignature string, publicKey *ecdsa.PublicKey) error {
+	if publicKey.Curve == nil || publicKey.Curve.Params().Name != curve.Params().Name {
+		return ErrInvKeyCrv
+	}
+	signature, err := DecodeBase64(encodedSignature)
+	if err != nil {
+		return ErrInvSecKey
+	}
+	size := (curve.Params().BitSize + 7) / 8
+	if len(signature) != 2*size {
+		return ErrInvSecKey
+	}
+	h := hash.New()
+	_, err = h.Write([]byte(message))
+	if err != nil {
+		return err
+	}
+	r := new(big.Int).SetBytes(signature[:size])
+	s := new(big.Int).SetBytes(signature[size:])
+	if !ecdsa.Verify(publicKey, h.Sum(nil), r, s) {
+		return ErrInvSecKey
+	}
+	return nil
+}
+
+// SignES256 signs a message string with an ECDSA private key on the P-256 curve using the ES256 algorithm
+// with additional base64 rawURLEncoding of the result signature.
+// Returns ErrInvKeyCrv if the key is not on the P-256 curve.
+func SignES256(message string, privateKey *ecdsa.PrivateKey) (string, error) {
+	return signES(crypto.SHA256, elliptic.P256(), message, privateKey)
+}
+
+// VerifyES256 verifies a base64 rawURLEncoded signature of a message string with an ECDSA public key
+// on the P-256 curve using the ES256 algorithm.
+// Returns ErrInvSecKey if the signature does not match or ErrInvKeyCrv if the key is not on the P-256 curve.
+func VerifyES256(message, signature string, publicKey *ecdsa.PublicKey) error {
+	return verifyES(crypto.SHA256, elliptic.P256(), message, signature, publicKey)
+}
+
+// SignES384 signs a message string with an ECDSA private key on the P-384 curve using the ES384 algorithm
+// with additional base64 rawURLEncoding of the result signature.
+// Returns ErrInvKeyCrv if the key is not on the P-384 curve.
+func SignES384(message string, privateKey *ecdsa.PrivateKey) (string, error) {
+	return signES(crypto.SHA384, elliptic.P384(), message, privateKey)
+}
+
+// VerifyES384 verifies a base64 rawURLEncoded signature of a message string with an ECDSA public key
+// on the P-384 curve using the ES384 algorithm.
+// Returns ErrInvSecKey if the signature does not match or ErrInvKeyCrv if the key is not on the P-384 curve.
+func VerifyES384(message, signature string, publicKey *ecdsa.PublicKey) error {
+	return verifyES(crypto.SHA384, elliptic.P384(), message, signature, publicKey)
+}
+
+// SignES512 signs a message string with an ECDSA private key on the P-521 curve using the ES512 algorithm
+// with additional base64 rawURLEncoding of the result signature.
+// Returns ErrInvKeyCrv if the key is not on the P-521 curve.
+func SignES512(message string, privateKey *ecdsa.PrivateKey) (string, error) {
+	return signES(crypto.SHA512, elliptic.P521(), message, privateKey)
+}
+
+// VerifyES512 verifies a base64 rawURLEncoded signature of a message string with an ECDSA public key
+// on the P-521 curve using the ES512 algorithm.
+// Returns ErrInvSecKey if the signature does not match or ErrInvKeyCrv if the key is not on the P-521 curve.
+func VerifyES512(message, signature string, publicKey *ecdsa.PublicKey) error {
+	return verifyES(crypto.SHA512, elliptic.P521(), message, signature, publicKey)
+}
+
+// SignEdDSA signs a message string with an Ed25519 private key using the EdDSA algorithm
+// with additional base64 rawURLEncoding of the result signature.
+// Returns ErrInvKeyTyp if the key does not have the size of an Ed25519 private key.
+func SignEdDSA(message string, privateKey ed25519.PrivateKey) (string, error) {
+	if len(privateKey) != ed25519.PrivateKeySize {
+		return "", ErrInvKeyTyp
+	}
+	return EncodeBase64(string(ed25519.Sign(privateKey, []byte(message)))), nil
+}
+
+// VerifyEdDSA verifies a base64 rawURLEncoded signature of a message string with an Ed25519 public key
+// using the EdDSA algorithm.
+// Returns ErrInvSecKey if the signature does not match or ErrInvKeyTyp if the key does not have the size
+// of an Ed25519 public key.
+func VerifyEdDSA(message, encodedSignature string, publicKey ed25519.PublicKey) error {
+	if len(publicKey) != ed25519.PublicKeySize {
+		return ErrInvKeyTyp
+	}
+	signature, err := DecodeBase64(encodedSignature)
+	if err != nil {
+		return ErrInvSecKey
+	}
+	if !ed25519.Verify(publicKey, []byte(message), signature) {
+		return ErrInvSecKey
+	}
+	return nil
+}
+
 func encryptRS(hash hash.Hash, message string, label []byte, publicKey rsa.PublicKey) (string, error) {
 	cipher, err := rsa.EncryptOAEP(hash, rand.Reader, &publicKey, []byte(message), label)
 	if err != nil {
@@ -92,38 +342,50 @@ func decryptRS(hash hash.Hash, encodedCipher string, label []byte, privateKey rs
 	return string(plaintext), nil
 }
 
-// EncryptRS256 signs a message string with a secret string and an RSA public key using the RS256 algorithm
+// EncryptRS256 encrypts a message string with a label and an RSA public key using RSA-OAEP with SHA-256
 // with additional base64 rawURLEncoding of the result cipher.
+//
+// Deprecated: This is the legacy RS256 scheme of gojwt, which is not compatible with RFC 7518. Use SignRS256 instead.
 func EncryptRS256(message string, label []byte, publicKey rsa.PublicKey) (string, error) {
 	return encryptRS(sha256.New(), message, label, publicKey)
 }
 
-// DecryptRS256 decrypts a base64 rawUrlEncoded cipher string with a secret string and an RSA private key
-// using the RS256 algorithm.
+// DecryptRS256 decrypts a base64 rawUrlEncoded cipher string with a label and an RSA private key
+// using RSA-OAEP with SHA-256.
+//
+// Deprecated: This is the legacy RS256 scheme of gojwt, which is not compatible with RFC 7518. Use VerifyRS256 instead.
 func DecryptRS256(encodedCipher string, label []byte, privateKey rsa.PrivateKey) (string, error) {
 	return decryptRS(sha256.New(), encodedCipher, label, privateKey)
 }
 
-// EncryptRS384 signs a message string with a secret string and an RSA public key using the RS384 algorithm
+// EncryptRS384 encrypts a message string with a label and an RSA public key using RSA-OAEP with SHA-384
 // with additional base64 rawURLEncoding of the result cipher.
+//
+// Deprecated: This is the legacy RS384 scheme of gojwt, which is not compatible with RFC 7518. Use SignRS384 instead.
 func EncryptRS384(message string, label []byte, publicKey rsa.PublicKey) (string, error) {
 	return encryptRS(sha512.New384(), message, label, publicKey)
 }
 
-// DecryptRS384 decrypts a base64 rawUrlEncoded cipher string with a secret string and an RSA private key
-// using the RS384 algorithm.
+// DecryptRS384 decrypts a base64 rawUrlEncoded cipher string with a label and an RSA private key
+// using RSA-OAEP with SHA-384.
+//
+// Deprecated: This is the legacy RS384 scheme of gojwt, which is not compatible with RFC 7518. Use VerifyRS384 instead.
 func DecryptRS384(encodedCipher string, label []byte, privateKey rsa.PrivateKey) (string, error) {
 	return decryptRS(sha512.New384(), encodedCipher, label, privateKey)
 }
 
-// EncryptRS512 signs a message string with a secret string and an RSA public key using the RS512 algorithm
+// EncryptRS512 encrypts a message string with a label and an RSA public key using RSA-OAEP with SHA-512
 // with additional base64 rawURLEncoding of the result cipher.
+//
+// Deprecated: This is the legacy RS512 scheme of gojwt, which is not compatible with RFC 7518. Use SignRS512 instead.
 func EncryptRS512(message string, label []byte, publicKey rsa.PublicKey) (string, error) {
 	return encryptRS(sha512.New(), message, label, publicKey)
 }
 
-// DecryptRS512 decrypts a base64 rawUrlEncoded cipher string with a secret string and an RSA private key
-// using the RS512 algorithm.
+// DecryptRS512 decrypts a base64 rawUrlEncoded cipher string with a label and an RSA private key
+// using RSA-OAEP with SHA-512.
+//
+// Deprecated: This is the legacy RS512 scheme of gojwt, which is not compatible with RFC 7518. Use VerifyRS512 instead.
 func DecryptRS512(encodedCipher string, label []byte, privateKey rsa.PrivateKey) (string, error) {
 	return decryptRS(sha512.New(), encodedCipher, label, privateKey)
 }
@@ -1,9 +1,15 @@
 package gojwt_test
 
 import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
 	"crypto/rand"
 	"crypto/rsa"
+	"crypto/sha256"
 	"github.com/tobyguelly/gojwt"
+	"math/big"
 	"testing"
 )
 
@@ -302,3 +308,357 @@ func TestEncryptAndDecryptRS512(t *testing.T) {
 		}
 	}
 }
+
+func TestSignAndVerifyRS(t *testing.T) {
+	privateKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Errorf("Failed test because of error: %s", err.Error())
+		t.FailNow()
+	}
+	wrongKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Errorf("Failed test because of error: %s", err.Error())
+		t.FailNow()
+	}
+	tests := []struct {
+		Input         string
+		Sign          func(string, *rsa.PrivateKey) (string, error)
+		Verify        func(string, string, *rsa.PublicKey) error
+		PublicKey     *rsa.PublicKey
+		ExpectedError error
+	}{
+		{
+			Input:         "Hello World",
+			Sign:          gojwt.SignRS256,
+			Verify:        gojwt.VerifyRS256,
+			PublicKey:     &privateKey.PublicKey,
+			ExpectedError: nil,
+		},
+		{
+			Input:         "Hello World",
+			Sign:          gojwt.SignRS384,
+			Verify:        gojwt.VerifyRS384,
+			PublicKey:     &privateKey.PublicKey,
+			ExpectedError: nil,
+		},
+		{
+			Input:         "Hello World",
+			Sign:          gojwt.SignRS512,
+			Verify:        gojwt.VerifyRS512,
+			PublicKey:     &privateKey.PublicKey,
+			ExpectedError: nil,
+		},
+		{
+			Input:         "Hello World",
+			Sign:          gojwt.SignRS256,
+			Verify:        gojwt.VerifyRS512,
+			PublicKey:     &privateKey.PublicKey,
+			ExpectedError: gojwt.ErrInvSecKey,
+		},
+		{
+			Input:         "Hello World",
+			Sign:          gojwt.SignRS256,
+			Verify:        gojwt.VerifyRS256,
+			PublicKey:     &wrongKey.PublicKey,
+			ExpectedError: gojwt.ErrInvSecKey,
+		},
+	}
+	for i, test := range tests {
+		signature, err := test.Sign(test.Input, privateKey)
+		if err != nil {
+			t.Errorf("Failed test because of error: %s", err.Error())
+			t.FailNow()
+		}
+		err = test.Verify(test.Input, signature, test.PublicKey)
+		if err == test.ExpectedError {
+			t.Logf("Passed %d/%d tests!", i+1, len(tests))
+		} else {
+			t.Errorf("Output and expected output did not match: %s\nFound:\t\t%v\nExpected:\t%v",
+				test.Input, err, test.ExpectedError,
+			)
+		}
+	}
+}
+
+func TestVerifyRS256_Interoperability(t *testing.T) {
+	// RSA key and token from the example in RFC 7515, Appendix A.2.
+	modulus, _ := gojwt.DecodeBase64("ofgWCuLjybRlzo0tZWJjNiuSfb4p4fAkd_wWJcyQoTbji9k0l8W26mPddxHmfHQp-Vaw-4qPCJrcS2mJPMEzP1Pt0Bm4d4QlL-yRT-SFd2lZS-pCgNMsD1W_YpRPEwOWvG6b32690r2jZ47soMZo9wGzjb_7OMg0LOL-bSf63kpaSHSXndS5z5rexMdbBYUsLA9e-KXBdQOS-UTo7WTBEMa2R2CapHg665xsmtdVMTBQY4uDZlxvb3qCo5ZwKh9kG4LT6_I5IhlJH7aGhyxXFvUK-DWNmoudF8NAco9_h9iaGNj8q2ethFkMLs91kzk2PAcDTW9gb54h4FRWyuXpoQ")
+	exponent, _ := gojwt.DecodeBase64("AQAB")
+	publicKey := &rsa.PublicKey{
+		N: new(big.Int).SetBytes(modulus),
+		E: int(new(big.Int).SetBytes(exponent).Int64()),
+	}
+	tests := []struct {
+		Input         string
+		Signature     string
+		ExpectedError error
+	}{
+		{
+			Input:         "eyJhbGciOiJSUzI1NiJ9.eyJpc3MiOiJqb2UiLA0KICJleHAiOjEzMDA4MTkzODAsDQogImh0dHA6Ly9leGFtcGxlLmNvbS9pc19yb290Ijp0cnVlfQ",
+			Signature:     "cC4hiUPoj9Eetdgtv3hF80EGrhuB__dzERat0XF9g2VtQgr9PJbu3XOiZj5RZmh7AAuHIm4Bh-0Qc_lF5YKt_O8W2Fp5jujGbds9uJdbF9CUAr7t1dnZcAcQjbKBYNX4BAynRFdiuB--f_nZLgrnbyTyWzO75vRK5h6xBArLIARNPvkSjtQBMHlb1L07Qe7K0GarZRmB_eSN9383LcOLn6_dO--xi12jzDwusC-eOkHWEsqtFZESc6BfI7noOPqvhJ1phCnvWh6IeYI2w9QOYEUipUTI8np6LbgGY9Fs98rqVt5AXLIhWkWywlVmtVrBp0igcN_IoypGlUPQGe77Rw",
+			ExpectedError: nil,
+		},
+		{
+			Input:         "eyJhbGciOiJSUzI1NiJ9.eyJpc3MiOiJqb2UifQ",
+			Signature:     "cC4hiUPoj9Eetdgtv3hF80EGrhuB__dzERat0XF9g2VtQgr9PJbu3XOiZj5RZmh7AAuHIm4Bh-0Qc_lF5YKt_O8W2Fp5jujGbds9uJdbF9CUAr7t1dnZcAcQjbKBYNX4BAynRFdiuB--f_nZLgrnbyTyWzO75vRK5h6xBArLIARNPvkSjtQBMHlb1L07Qe7K0GarZRmB_eSN9383LcOLn6_dO--xi12jzDwusC-eOkHWEsqtFZESc6BfI7noOPqvhJ1phCnvWh6IeYI2w9QOYEUipUTI8np6LbgGY9Fs98rqVt5AXLIhWkWywlVmtVrBp0igcN_IoypGlUPQGe77Rw",
+			ExpectedError: gojwt.ErrInvSecKey,
+		},
+	}
+	for i, test := range tests {
+		err := gojwt.VerifyRS256(test.Input, test.Signature, publicKey)
+		if err == test.ExpectedError {
+			t.Logf("Passed %d/%d tests!", i+1, len(tests))
+		} else {
+			t.Errorf("Output and expected output did not match: %s\nFound:\t\t%v\nExpected:\t%v",
+				test.Input, err, test.ExpectedError,
+			)
+		}
+	}
+}
+
+func TestSignAndVerifyPS(t *testing.T) {
+	privateKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Errorf("Failed test because of error: %s", err.Error())
+		t.FailNow()
+	}
+	maxSaltLength := func(message string, privateKey *rsa.PrivateKey) (string, error) {
+		digest := sha256.Sum256([]byte(message))
+		signature, err := rsa.SignPSS(rand.Reader, privateKey, crypto.SHA256, digest[:], &rsa.PSSOptions{
+			SaltLength: rsa.PSSSaltLengthAuto,
+		})
+		return gojwt.EncodeBase64(string(signature)), err
+	}
+	tests := []struct {
+		Input         string
+		Sign          func(string, *rsa.PrivateKey) (string, error)
+		Verify        func(string, string, *rsa.PublicKey) error
+		ExpectedError error
+	}{
+		{
+			Input:         "Hello World",
+			Sign:          gojwt.SignPS256,
+			Verify:        gojwt.VerifyPS256,
+			ExpectedError: nil,
+		},
+		{
+			Input:         "Hello World",
+			Sign:          gojwt.SignPS384,
+			Verify:        gojwt.VerifyPS384,
+			ExpectedError: nil,
+		},
+		{
+			Input:         "Hello World",
+			Sign:          gojwt.SignPS512,
+			Verify:        gojwt.VerifyPS512,
+			ExpectedError: nil,
+		},
+		{
+			Input:         "Hello World",
+			Sign:          gojwt.SignPS256,
+			Verify:        gojwt.VerifyPS384,
+			ExpectedError: gojwt.ErrInvSecKey,
+		},
+		{
+			Input:         "Hello World",
+			Sign:          gojwt.SignRS256,
+			Verify:        gojwt.VerifyPS256,
+			ExpectedError: gojwt.ErrInvSecKey,
+		},
+		{
+			Input:         "Hello World",
+			Sign:          maxSaltLength,
+			Verify:        gojwt.VerifyPS256,
+			ExpectedError: gojwt.ErrInvSecKey,
+		},
+	}
+	for i, test := range tests {
+		signature, err := test.Sign(test.Input, privateKey)
+		if err != nil {
+			t.Errorf("Failed test because of error: %s", err.Error())
+			t.FailNow()
+		}
+		err = test.Verify(test.Input, signature, &privateKey.PublicKey)
+		if err == test.ExpectedError {
+			t.Logf("Passed %d/%d tests!", i+1, len(tests))
+		} else {
+			t.Errorf("Output and expected output did not match: %s\nFound:\t\t%v\nExpected:\t%v",
+				test.Input, err, test.ExpectedError,
+			)
+		}
+	}
+}
+
+func TestSignAndVerifyES(t *testing.T) {
+	keys := map[elliptic.Curve]*ecdsa.PrivateKey{}
+	for _, curve := range []elliptic.Curve{elliptic.P256(), elliptic.P384(), elliptic.P521()} {
+		privateKey, err := ecdsa.GenerateKey(curve, rand.Reader)
+		if err != nil {
+			t.Errorf("Failed test because of error: %s", err.Error())
+			t.FailNow()
+		}
+		keys[curve] = privateKey
+	}
+	tests := []struct {
+		Input         string
+		Sign          func(string, *ecdsa.PrivateKey) (string, error)
+		Verify        func(string, string, *ecdsa.PublicKey) error
+		PrivateKey    *ecdsa.PrivateKey
+		PublicKey     *ecdsa.PublicKey
+		SignatureSize int
+		ExpectedError error
+	}{
+		{
+			Input:         "Hello World",
+			Sign:          gojwt.SignES256,
+			Verify:        gojwt.VerifyES256,
+			PrivateKey:    keys[elliptic.P256()],
+			PublicKey:     &keys[elliptic.P256()].PublicKey,
+			SignatureSize: 64,
+			ExpectedError: nil,
+		},
+		{
+			Input:         "Hello World",
+			Sign:          gojwt.SignES384,
+			Verify:        gojwt.VerifyES384,
+			PrivateKey:    keys[elliptic.P384()],
+			PublicKey:     &keys[elliptic.P384()].PublicKey,
+			SignatureSize: 96,
+			ExpectedError: nil,
+		},
+		{
+			Input:         "Hello World",
+			Sign:          gojwt.SignES512,
+			Verify:        gojwt.VerifyES512,
+			PrivateKey:    keys[elliptic.P521()],
+			PublicKey:     &keys[elliptic.P521()].PublicKey,
+			SignatureSize: 132,
+			ExpectedError: nil,
+		},
+		{
+			Input:         "Hello World",
+			Sign:          gojwt.SignES384,
+			Verify:        gojwt.VerifyES384,
+			PrivateKey:    keys[elliptic.P256()],
+			PublicKey:     &keys[elliptic.P256()].PublicKey,
+			ExpectedError: gojwt.ErrInvKeyCrv,
+		},
+		{
+			Input:         "Hello World",
+			Sign:          gojwt.SignES256,
+			Verify:        gojwt.VerifyES256,
+			PrivateKey:    keys[elliptic.P256()],
+			PublicKey:     &keys[elliptic.P384()].PublicKey,
+			SignatureSize: 64,
+			ExpectedError: gojwt.ErrInvKeyCrv,
+		},
+	}
+	for i, test := range tests {
+		signature, err := test.Sign(test.Input, test.PrivateKey)
+		if err == nil {
+			raw, _ := gojwt.DecodeBase64(signature)
+			if len(raw) != test.SignatureSize {
+				t.Errorf("Output and expected output did not match: %s\nFound:\t\t%d\nExpected:\t%d",
+					test.Input, len(raw), test.SignatureSize,
+				)
+			}
+			err = test.Verify(test.Input, signature, test.PublicKey)
+		}
+		if err == test.ExpectedError {
+			t.Logf("Passed %d/%d tests!", i+1, len(tests))
+		} else {
+			t.Errorf("Output and expected output did not match: %s\nFound:\t\t%v\nExpected:\t%v",
+				test.Input, err, test.ExpectedError,
+			)
+		}
+	}
+}
+
+func TestVerifyES256_Interoperability(t *testing.T) {
+	// EC key and token from the example in RFC 7515, Appendix A.3.
+	x, _ := gojwt.DecodeBase64("f83OJ3D2xF1Bg8vub9tLe1gHMzV76e8Tus9uPHvRVEU")
+	y, _ := gojwt.DecodeBase64("x_FEzRu9m36HLN_tue659LNpXW6pCyStikYjKIWI5a0")
+	publicKey := &ecdsa.PublicKey{
+		Curve: elliptic.P256(),
+		X:     new(big.Int).SetBytes(x),
+		Y:     new(big.Int).SetBytes(y),
+	}
+	tests := []struct {
+		Input         string
+		Signature     string
+		ExpectedError error
+	}{
+		{
+			Input:         "eyJhbGciOiJFUzI1NiJ9.eyJpc3MiOiJqb2UiLA0KICJleHAiOjEzMDA4MTkzODAsDQogImh0dHA6Ly9leGFtcGxlLmNvbS9pc19yb290Ijp0cnVlfQ",
+			Signature:     "DtEhU3ljbEg8L38VWAfUAqOyKAM6-Xx-F4GawxaepmXFCgfTjDxw5djxLa8ISlSApmWQxfKTUJqPP3-Kg6NU1Q",
+			ExpectedError: nil,
+		},
+		{
+			Input:         "eyJhbGciOiJFUzI1NiJ9.eyJpc3MiOiJqb2UiLA0KICJleHAiOjEzMDA4MTkzODAsDQogImh0dHA6Ly9leGFtcGxlLmNvbS9pc19yb290Ijp0cnVlfQ",
+			Signature:     "MEUCIA7RIVN5Y2xIPC9_FVgH1AKjsigDOvl8fheBmsMWnqZlAiEAxQoH04w8cOXY8S2vCEpUgKZlkMXyk1CajzN_ioOjVNU",
+			ExpectedError: gojwt.ErrInvSecKey,
+		},
+	}
+	for i, test := range tests {
+		err := gojwt.VerifyES256(test.Input, test.Signature, publicKey)
+		if err == test.ExpectedError {
+			t.Logf("Passed %d/%d tests!", i+1, len(tests))
+		} else {
+			t.Errorf("Output and expected output did not match: %s\nFound:\t\t%v\nExpected:\t%v",
+				test.Input, err, test.ExpectedError,
+			)
+		}
+	}
+}
+
+func TestSignAndVerifyEdDSA(t *testing.T) {
+	// Ed25519 key and signature from the example in RFC 8037, Appendix A.4.
+	seed, _ := gojwt.DecodeBase64("nWGxne_9WmC6hEr0kuwsxERJxWl7MmkZcDusAxyuf2A")
+	privateKey := ed25519.NewKeyFromSeed(seed)
+	_, wrongKey, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Errorf("Failed test because of error: %s", err.Error())
+		t.FailNow()
+	}
+	tests := []struct {
+		Input          string
+		PrivateKey     ed25519.PrivateKey
+		ExpectedOutput string
+		ExpectedError  error
+	}{
+		{
+			Input:          "eyJhbGciOiJFZERTQSJ9.RXhhbXBsZSBvZiBFZDI1NTE5IHNpZ25pbmc",
+			PrivateKey:     privateKey,
+			ExpectedOutput: "hgyY0il_MGCjP0JzlnLWG1PPOt7-09PGcvMg3AIbQR6dWbhijcNR4ki4iylGjg5BhVsPt9g7sVvpAr_MuM0KAg",
+			ExpectedError:  nil,
+		},
+		{
+			Input:         "eyJhbGciOiJFZERTQSJ9.RXhhbXBsZSBvZiBFZDI1NTE5IHNpZ25pbmc",
+			PrivateKey:    wrongKey,
+			ExpectedError: gojwt.ErrInvSecKey,
+		},
+		{
+			Input:         "eyJhbGciOiJFZERTQSJ9.RXhhbXBsZSBvZiBFZDI1NTE5IHNpZ25pbmc",
+			PrivateKey:    privateKey[:16],
+			ExpectedError: gojwt.ErrInvKeyTyp,
+		},
+	}
+	for i, test := range tests {
+		signature, err := gojwt.SignEdDSA(test.Input, test.PrivateKey)
+		if err == nil {
+			if test.ExpectedOutput != "" && signature != test.ExpectedOutput {
+				t.Errorf("Output and expected output did not match: %s\nFound:\t\t%s\nExpected:\t%s",
+					test.Input, signature, test.ExpectedOutput,
+				)
+			}
+			err = gojwt.VerifyEdDSA(test.Input, signature, privateKey.Public().(ed25519.PublicKey))
+		}
+		if err == test.ExpectedError {
+			t.Logf("Passed %d/%d tests!", i+1, len(tests))
+		} else {
+			t.Errorf("Output and expected output did not match: %s\nFound:\t\t%v\nExpected:\t%v",
+				test.Input, err, test.ExpectedError,
+			)
+		}
+	}
+}
@@ -0,0 +1,133 @@
+package gojwt
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// ValidationError describes a single failed check during the validation of a JWT.
+// It can be compared to the sentinel errors of this package with errors.Is, where
+// ErrTokExpd and ErrTokNotAct are also reported as ErrInvTokPrd.
+type ValidationError struct {
+
+	// Err is the sentinel error describing the kind of the failure, like ErrTokExpd or ErrInvTokIss.
+	Err error
+
+	// Claim is the name of the claim or header which failed the check, like "exp" or "iss".
+	// It is empty if the signature of the token did not match.
+	Claim string
+
+	// Value is the offending value of the claim.
+	Value interface{}
+
+	// Expected is the value the claim was checked against, like the current time or the expected issuers.
+	Expected interface{}
+}
+
+// Error is the implementation of the error interface.
+func (this *ValidationError) Error() string {
+	if this.Claim == "" {
+		return this.Err.Error()
+	}
+	return fmt.Sprintf("%s: %s is %s, expected %s",
+		this.Err.Error(), this.Claim, formatValue(this.Value), formatValue(this.Expected),
+	)
+}
+
+// Unwrap returns the sentinel error describing the kind of the failure.
+func (this *ValidationError) Unwrap() error {
+	return this.Err
+}
+
+// Is reports whether the ValidationError matches a target error.
+// Expired and not yet valid tokens match ErrInvTokPrd, to stay compatible with previous versions.
+func (this *ValidationError) Is(target error) bool {
+	return target == ErrInvTokPrd && (this.Err == ErrTokExpd || this.Err == ErrTokNotAct)
+}
+
+// ValidationErrors aggregates all failed checks during the validation of a JWT.
+// It matches a target error with errors.Is if any of its ValidationError values matches,
+// and errors.As with a *ValidationError target returns the first ValidationError.
+type ValidationErrors []*ValidationError
+
+// Error is the implementation of the error interface.
+func (this ValidationErrors) Error() string {
+	messages := make([]string, len(this))
+	for i, err := range this {
+		messages[i] = err.Error()
+	}
+	return strings.Join(messages, "; ")
+}
+
+// Is reports whether any of the ValidationError values matches a target error.
+func (this ValidationErrors) Is(target error) bool {
+	for _, err := range this {
+		if errors.Is(err, target) {
+			return true
+		}
+	}
+	return false
+}
+
+// As finds the first ValidationError, which can be assigned to the target.
+func (this ValidationErrors) As(target interface{}) bool {
+	for _, err := range this {
+		if errors.As(err, target) {
+			return true
+		}
+	}
+	return false
+}
+
+// Find returns the first ValidationError for a claim or nil, if the claim has not failed.
+func (this ValidationErrors) Find(claim string) *ValidationError {
+	for _, err := range this {
+		if err.Claim == claim {
+			return err
+		}
+	}
+	return nil
+}
+
+// storeError wraps the error of a RevocationStore or a CutoffStore, so it matches ErrStoreUnav and the error of the store.
+type storeError struct {
+	err error
+}
+
+// Error is the implementation of the error interface.
+func (this *storeError) Error() string {
+	return ErrStoreUnav.Error() + ": " + this.err.Error()
+}
+
+// Unwrap returns the error of the store.
+func (this *storeError) Unwrap() error {
+	return this.err
+}
+
+// Is reports whether the target error is ErrStoreUnav.
+func (this *storeError) Is(target error) bool {
+	return target == ErrStoreUnav
+}
+
+func (this ValidationErrors) orNil() error {
+	if len(this) == 0 {
+		return nil
+	}
+	return this
+}
+
+func formatValue(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return "<none>"
+	case time.Time:
+		return v.UTC().Format(time.RFC3339)
+	case []string:
+		return "[" + strings.Join(v, ", ") + "]"
+	case string:
+		return fmt.Sprintf("%q", v)
+	}
+	return fmt.Sprint(value)
+}
@@ -0,0 +1,136 @@
+package gojwt_test
+
+import (
+	"errors"
+	"github.com/tobyguelly/gojwt"
+	"testing"
+	"time"
+)
+
+func TestValidationError_Is(t *testing.T) {
+	tests := []struct {
+		Input          error
+		Target         error
+		ExpectedOutput bool
+	}{
+		{
+			Input:          &gojwt.ValidationError{Err: gojwt.ErrTokExpd, Claim: "exp"},
+			Target:         gojwt.ErrTokExpd,
+			ExpectedOutput: true,
+		},
+		{
+			Input:          &gojwt.ValidationError{Err: gojwt.ErrTokExpd, Claim: "exp"},
+			Target:         gojwt.ErrInvTokPrd,
+			ExpectedOutput: true,
+		},
+		{
+			Input:          &gojwt.ValidationError{Err: gojwt.ErrTokNotAct, Claim: "nbf"},
+			Target:         gojwt.ErrInvTokPrd,
+			ExpectedOutput: true,
+		},
+		{
+			Input:          &gojwt.ValidationError{Err: gojwt.ErrTokNotAct, Claim: "nbf"},
+			Target:         gojwt.ErrTokExpd,
+			ExpectedOutput: false,
+		},
+		{
+			Input:          &gojwt.ValidationError{Err: gojwt.ErrInvTokIss, Claim: "iss"},
+			Target:         gojwt.ErrInvTokPrd,
+			ExpectedOutput: false,
+		},
+		{
+			Input: gojwt.ValidationErrors{
+				&gojwt.ValidationError{Err: gojwt.ErrInvTokIss, Claim: "iss"},
+				&gojwt.ValidationError{Err: gojwt.ErrTokExpd, Claim: "exp"},
+			},
+			Target:         gojwt.ErrInvTokPrd,
+			ExpectedOutput: true,
+		},
+		{
+			Input: gojwt.ValidationErrors{
+				&gojwt.ValidationError{Err: gojwt.ErrInvTokIss, Claim: "iss"},
+			},
+			Target:         gojwt.ErrInvTokAud,
+			ExpectedOutput: false,
+		},
+	}
+	for i, test := range tests {
+		res := errors.Is(test.Input, test.Target)
+		if res == test.ExpectedOutput {
+			t.Logf("Passed %d/%d tests!", i+1, len(tests))
+		} else {
+			t.Errorf("Output and expected output did not match: %s\nFound:\t\t%t\nExpected:\t%t",
+				test.Input, res, test.ExpectedOutput,
+			)
+		}
+	}
+}
+
+func TestValidationErrors_Aggregate(t *testing.T) {
+	now := time.Unix(1000, 0)
+	validator := gojwt.NewValidator(
+		gojwt.UseClock(func() time.Time {
+			return now
+		}),
+		gojwt.ExpectIssuers("foo"),
+		gojwt.ExpectAudience("api"),
+	)
+	token, err := gojwt.WithBuilder().
+		Audience("web").
+		ExpirationTime(time.Unix(900, 0)).
+		Sign("secret")
+	if err != nil {
+		t.Errorf("Failed test because of error: %s", err.Error())
+		t.FailNow()
+	}
+	_, err = validator.Parse(token, "secret")
+	var errs gojwt.ValidationErrors
+	if !errors.As(err, &errs) || len(errs) != 3 {
+		t.Errorf("Failed test because of unexpected error: %v", err)
+		t.FailNow()
+	}
+	var first *gojwt.ValidationError
+	if !errors.As(err, &first) || first.Claim != "exp" {
+		t.Errorf("Failed test because of unexpected first error: %v", first)
+		t.FailNow()
+	}
+	tests := []struct {
+		Claim            string
+		ExpectedError    error
+		ExpectedValue    interface{}
+		ExpectedExpected interface{}
+	}{
+		{
+			Claim:            "exp",
+			ExpectedError:    gojwt.ErrTokExpd,
+			ExpectedValue:    time.Unix(900, 0),
+			ExpectedExpected: now,
+		},
+		{
+			Claim:         "iss",
+			ExpectedError: gojwt.ErrInvTokIss,
+			ExpectedValue: "gojwt",
+		},
+		{
+			Claim:         "aud",
+			ExpectedError: gojwt.ErrInvTokAud,
+		},
+	}
+	for i, test := range tests {
+		res := errs.Find(test.Claim)
+		if res != nil && res.Err == test.ExpectedError && (test.ExpectedValue == nil || res.Value == test.ExpectedValue) &&
+			(test.ExpectedExpected == nil || res.Expected == test.ExpectedExpected) {
+			t.Logf("Passed %d/%d tests!", i+1, len(tests))
+		} else {
+			t.Errorf("Output and expected output did not match: %s\nFound:\t\t%v\nExpected:\t%v",
+				test.Claim, res, test.ExpectedError,
+			)
+		}
+	}
+	expected := "TOKEN EXPIRED: exp is 1970-01-01T00:15:00Z, expected 1970-01-01T00:16:40Z; " +
+		"INVALID TOKEN ISSUER: iss is \"gojwt\", expected [foo]; " +
+		"INVALID TOKEN AUDIENCE: aud is [web], expected [api]"
+	if err.Error() != expected {
+		t.Errorf("Output and expected output did not match:\nFound:\t\t%s\nExpected:\t%s", err.Error(), expected)
+	}
+}
@@ -1,12 +1,38 @@
 package main
 
-import "github.com/tobyguelly/gojwt"
+import (
+	"fmt"
+	"github.com/tobyguelly/gojwt"
+)
+
+const (
+	algorithm = "CUSTOM"
+)
 
 func main() {
-	gojwt.Algorithms = gojwt.AlgorithmMap{
-		gojwt.AlgHS256: func(message, secret string) (string, error) {
+	registry := gojwt.NewRegistry()
+	registry.Register(algorithm, func(key interface{}) (gojwt.Signer, error) {
+		return gojwt.NewSigner(algorithm, func(message string) (string, error) {
 			// TODO Custom Implementation
 			return "", nil
-		},
+		}), nil
+	}, func(key interface{}) (gojwt.Verifier, error) {
+		return gojwt.NewVerifier(algorithm, func(message, signature string) error {
+			// TODO Custom Implementation
+			return nil
+		}), nil
+	})
+
+	signer, err := registry.Signer(algorithm, nil)
+	if err != nil {
+		fmt.Println(err.Error())
+		return
+	}
+
+	token, err := gojwt.WithBuilder().
+		Custom("username", "admin").
+		SignWith(signer)
+	if err == nil {
+		fmt.Println(token)
 	}
 }
@@ -0,0 +1,117 @@
+package gojwt
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+)
+
+// TokenExtractor extracts a token from an HTTP request.
+// Returns ErrTokMis if the request does not contain a token and ErrBadReq if the token in the request is malformed.
+type TokenExtractor func(r *http.Request) (token string, err error)
+
+// Load extracts a token from an HTTP request and loads it with LoadJWT, without validating it.
+func (this TokenExtractor) Load(r *http.Request) (*JWT, error) {
+	token, err := this(r)
+	if err != nil {
+		return nil, err
+	}
+	return LoadJWT(token)
+}
+
+// BearerExtractor returns a TokenExtractor, which extracts tokens from the Authorization header
+// with the Bearer scheme as specified in RFC 6750 section 2.1.
+func BearerExtractor() TokenExtractor {
+	return HeaderExtractor("Authorization", "Bearer ")
+}
+
+// HeaderExtractor returns a TokenExtractor, which extracts tokens from a header, where the value must start
+// with a prefix, like "Bearer ". The prefix is compared case-insensitive and may be empty.
+// Headers with a different prefix are treated like missing headers.
+func HeaderExtractor(name, prefix string) TokenExtractor {
+	return func(r *http.Request) (string, error) {
+		values := r.Header.Values(name)
+		if len(values) == 0 {
+			return "", ErrTokMis
+		}
+		if len(values) > 1 {
+			return "", ErrBadReq
+		}
+		token := values[0]
+		if prefix != "" {
+			if len(token) >= len(prefix) && strings.EqualFold(token[:len(prefix)], prefix) {
+				token = token[len(prefix):]
+			} else if strings.EqualFold(strings.TrimSpace(token), strings.TrimSpace(prefix)) {
+				token = ""
+			} else {
+				return "", ErrTokMis
+			}
+		}
+		return nonEmptyToken(token)
+	}
+}
+
+// CookieExtractor returns a TokenExtractor, which extracts tokens from a cookie.
+func CookieExtractor(name string) TokenExtractor {
+	return func(r *http.Request) (string, error) {
+		cookie, err := r.Cookie(name)
+		if errors.Is(err, http.ErrNoCookie) {
+			return "", ErrTokMis
+		}
+		if err != nil {
+			return "", ErrBadReq
+		}
+		return nonEmptyToken(cookie.Value)
+	}
+}
+
+// QueryExtractor returns a TokenExtractor, which extracts tokens from a query parameter of the URL,
+// like the access_token parameter specified in RFC 6750 section 2.3.
+func QueryExtractor(name string) TokenExtractor {
+	return func(r *http.Request) (string, error) {
+		return singleValue(r.URL.Query()[name])
+	}
+}
+
+// FormExtractor returns a TokenExtractor, which extracts tokens from a field of a form encoded request body,
+// like the access_token field specified in RFC 6750 section 2.2.
+func FormExtractor(name string) TokenExtractor {
+	return func(r *http.Request) (string, error) {
+		if err := r.ParseForm(); err != nil {
+			return "", ErrBadReq
+		}
+		return singleValue(r.PostForm[name])
+	}
+}
+
+// ChainExtractor returns a TokenExtractor, which tries a list of extractors in order and returns the first token found.
+// Errors other than ErrTokMis are returned immediately and ErrTokMis is returned if no extractor found a token.
+func ChainExtractor(extractors ...TokenExtractor) TokenExtractor {
+	return func(r *http.Request) (string, error) {
+		for _, extractor := range extractors {
+			token, err := extractor(r)
+			if !errors.Is(err, ErrTokMis) {
+				return token, err
+			}
+		}
+		return "", ErrTokMis
+	}
+}
+
+func singleValue(values []string) (string, error) {
+	if len(values) == 0 {
+		return "", ErrTokMis
+	}
+	if len(values) > 1 {
+		return "", ErrBadReq
+	}
+	return nonEmptyToken(values[0])
+}
+
+func nonEmptyToken(token string) (string, error) {
+	token = strings.TrimSpace(token)
+	if token == "" {
+		return "", ErrBadReq
+	}
+	return token, nil
+}
@@ -0,0 +1,174 @@
+package gojwt_test
+
+import (
+	"errors"
+	"github.com/tobyguelly/gojwt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestTokenExtractor(t *testing.T) {
+	request := func(target string, header map[string]string, body string) *http.Request {
+		var r *http.Request
+		if body == "" {
+			r = httptest.NewRequest(http.MethodGet, target, nil)
+		} else {
+			r = httptest.NewRequest(http.MethodPost, target, strings.NewReader(body))
+			r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		}
+		for name, value := range header {
+			r.Header.Set(name, value)
+		}
+		return r
+	}
+	chain := gojwt.ChainExtractor(gojwt.BearerExtractor(), gojwt.CookieExtractor("token"), gojwt.QueryExtractor("access_token"))
+	tests := []struct {
+		Extractor     gojwt.TokenExtractor
+		Request       *http.Request
+		Expected      string
+		ExpectedError error
+	}{
+		{
+			Extractor: gojwt.BearerExtractor(),
+			Request:   request("/", map[string]string{"Authorization": "Bearer abc"}, ""),
+			Expected:  "abc",
+		},
+		{
+			Extractor: gojwt.BearerExtractor(),
+			Request:   request("/", map[string]string{"Authorization": "BEARER  abc "}, ""),
+			Expected:  "abc",
+		},
+		{
+			Extractor:     gojwt.BearerExtractor(),
+			Request:       request("/", map[string]string{"Authorization": "Basic abc"}, ""),
+			ExpectedError: gojwt.ErrTokMis,
+		},
+		{
+			Extractor:     gojwt.BearerExtractor(),
+			Request:       request("/", map[string]string{"Authorization": "Bearer "}, ""),
+			ExpectedError: gojwt.ErrBadReq,
+		},
+		{
+			Extractor: gojwt.HeaderExtractor("X-Api-Token", ""),
+			Request:   request("/", map[string]string{"X-Api-Token": "abc"}, ""),
+			Expected:  "abc",
+		},
+		{
+			Extractor: gojwt.HeaderExtractor("X-Api-Token", "Token="),
+			Request:   request("/", map[string]string{"X-Api-Token": "Token=abc"}, ""),
+			Expected:  "abc",
+		},
+		{
+			Extractor:     gojwt.HeaderExtractor("X-Api-Token", ""),
+			Request:       request("/", nil, ""),
+			ExpectedError: gojwt.ErrTokMis,
+		},
+		{
+			Extractor: gojwt.CookieExtractor("token"),
+			Request:   request("/", map[string]string{"Cookie": "session=xyz; token=abc"}, ""),
+			Expected:  "abc",
+		},
+		{
+			Extractor:     gojwt.CookieExtractor("token"),
+			Request:       request("/", map[string]string{"Cookie": "session=xyz"}, ""),
+			ExpectedError: gojwt.ErrTokMis,
+		},
+		{
+			Extractor:     gojwt.CookieExtractor("token"),
+			Request:       request("/", map[string]string{"Cookie": "token="}, ""),
+			ExpectedError: gojwt.ErrBadReq,
+		},
+		{
+			Extractor: gojwt.QueryExtractor("access_token"),
+			Request:   request("/download?access_token=abc", nil, ""),
+			Expected:  "abc",
+		},
+		{
+			Extractor:     gojwt.QueryExtractor("access_token"),
+			Request:       request("/download?access_token=abc&access_token=def", nil, ""),
+			ExpectedError: gojwt.ErrBadReq,
+		},
+		{
+			Extractor: gojwt.FormExtractor("access_token"),
+			Request:   request("/", nil, "access_token=abc"),
+			Expected:  "abc",
+		},
+		{
+			Extractor:     gojwt.FormExtractor("access_token"),
+			Request:       request("/?access_token=abc", nil, ""),
+			ExpectedError: gojwt.ErrTokMis,
+		},
+		{
+			Extractor: chain,
+			Request:   request("/?access_token=def", map[string]string{"Cookie": "token=abc"}, ""),
+			Expected:  "abc",
+		},
+		{
+			Extractor: chain,
+			Request:   request("/?access_token=def", nil, ""),
+			Expected:  "def",
+		},
+		{
+			Extractor:     chain,
+			Request:       request("/?access_token=def", map[string]string{"Authorization": "Bearer"}, ""),
+			ExpectedError: gojwt.ErrBadReq,
+		},
+		{
+			Extractor:     chain,
+			Request:       request("/", nil, ""),
+			ExpectedError: gojwt.ErrTokMis,
+		},
+	}
+	for i, test := range tests {
+		token, err := test.Extractor(test.Request)
+		if token == test.Expected && errors.Is(err, test.ExpectedError) {
+			t.Logf("Passed %d/%d tests!", i+1, len(tests))
+		} else {
+			t.Errorf("Output and expected output did not match: %s\nFound:\t\t%s %v\nExpected:\t%s %v",
+				test.Request.URL, token, err, test.Expected, test.ExpectedError,
+			)
+		}
+	}
+}
+
+func TestTokenExtractor_Load(t *testing.T) {
+	jwt := gojwt.NewJWT()
+	jwt.Payload.Subject = "Joe"
+	token, err := jwt.SignParse("secret")
+	if err != nil {
+		t.Errorf("Failed test because of error: %s", err.Error())
+		t.FailNow()
+	}
+	tests := []struct {
+		Input         string
+		Expected      string
+		ExpectedError error
+	}{
+		{
+			Input:    token,
+			Expected: "Joe",
+		},
+		{
+			Input:         "",
+			ExpectedError: gojwt.ErrBadReq,
+		},
+		{
+			Input:         "abc",
+			ExpectedError: gojwt.ErrBadJWTTok,
+		},
+	}
+	for i, test := range tests {
+		r := httptest.NewRequest(http.MethodGet, "/", nil)
+		r.AddCookie(&http.Cookie{Name: "token", Value: test.Input})
+		res, err := gojwt.CookieExtractor("token").Load(r)
+		if errors.Is(err, test.ExpectedError) && (err != nil || res.Payload.Subject == test.Expected) {
+			t.Logf("Passed %d/%d tests!", i+1, len(tests))
+		} else {
+			t.Errorf("Output and expected output did not match: %s\nFound:\t\t%v\nExpected:\t%v",
+				test.Input, err, test.ExpectedError,
+			)
+		}
+	}
+}
@@ -13,11 +13,14 @@ type Header struct {
 
 	// Type indicates the type of the token, must be "JWT" for JWT tokens.
 	Type string `json:"typ"`
+
+	// KeyID identifies the key used for signing a JWT, which is selected by its kid member from a JWKSet, not required.
+	KeyID string `json:"kid,omitempty"`
 }
 
 // IsEmpty returns a bool, whether the Header is empty or not.
 func (this *Header) IsEmpty() bool {
-	return this.Algorithm == "" && this.Type == "" && this.ContentType == ""
+	return this.Algorithm == "" && this.Type == "" && this.ContentType == "" && this.KeyID == ""
 }
 
 // Json formats the Header into JSON format.
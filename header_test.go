@@ -24,6 +24,12 @@ func TestHeader_IsEmpty(t *testing.T) {
 			},
 			ExpectedOutput: false,
 		},
+		{
+			Input: gojwt.Header{
+				KeyID: "key-1",
+			},
+			ExpectedOutput: false,
+		},
 	}
 	for i, test := range tests {
 		res := test.Input.IsEmpty()
@@ -61,6 +67,14 @@ func TestHeader_Json(t *testing.T) {
 			},
 			ExpectedOutput: "{\"alg\":\"HS256\",\"cty\":\"JWT\",\"typ\":\"JWT\"}",
 		},
+		{
+			Input: gojwt.Header{
+				Algorithm: gojwt.AlgES256,
+				Type:      gojwt.TypJWT,
+				KeyID:     "key-1",
+			},
+			ExpectedOutput: "{\"alg\":\"ES256\",\"typ\":\"JWT\",\"kid\":\"key-1\"}",
+		},
 	}
 	for i, test := range tests {
 		res, err := test.Input.Json()
@@ -0,0 +1,245 @@
+package gojwt
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"strings"
+)
+
+// JWEHeader is the protected header of a JWE token as specified in RFC 7516.
+type JWEHeader struct {
+
+	// Algorithm is the identification of the key management algorithm, which encrypts the content encryption key.
+	Algorithm string `json:"alg"`
+
+	// Encryption is the identification of the content encryption algorithm, which encrypts the plaintext.
+	Encryption string `json:"enc"`
+
+	// ContentType indicates the content type of the plaintext, which is "JWT" for nested tokens, not required.
+	ContentType string `json:"cty,omitempty"`
+
+	// Type indicates the type of the token, not required.
+	Type string `json:"typ,omitempty"`
+
+	// KeyID identifies the key used for encrypting the content encryption key, not required.
+	KeyID string `json:"kid,omitempty"`
+
+	// EphemeralPublicKey is the public key of the sender for the ECDH-ES key agreement, which is set by Encrypt.
+	EphemeralPublicKey *JWK `json:"epk,omitempty"`
+
+	// AgreementPartyUInfo is the base64 rawURLEncoded information about the sender for the ECDH-ES key agreement,
+	// not required.
+	AgreementPartyUInfo string `json:"apu,omitempty"`
+
+	// AgreementPartyVInfo is the base64 rawURLEncoded information about the recipient for the ECDH-ES key agreement,
+	// not required.
+	AgreementPartyVInfo string `json:"apv,omitempty"`
+
+	// Compression is the identification of the compression algorithm applied to the plaintext, not required.
+	// Compression is not supported, so JWEs with this member are rejected.
+	Compression string `json:"zip,omitempty"`
+
+	// Critical holds the names of the header members, which must be understood for decrypting the JWE, not required.
+	// No extensions are supported, so JWEs with this member are rejected.
+	Critical []string `json:"crit,omitempty"`
+}
+
+var (
+	supportedJWECriticalHeaders = map[string]bool{}
+)
+
+// JWE is a token, whose payload is encrypted, serialized in the JWE compact serialization specified in RFC 7516.
+type JWE struct {
+
+	// Header is the protected header of the JWE, which is integrity protected by the encryption.
+	Header JWEHeader
+
+	rawHeader    string
+	encryptedKey []byte
+	iv           []byte
+	ciphertext   []byte
+	tag          []byte
+}
+
+// NewJWE creates a new JWE object with a key management and a content encryption algorithm.
+func NewJWE(alg, enc string) JWE {
+	return JWE{
+		Header: JWEHeader{
+			Algorithm:  alg,
+			Encryption: enc,
+		},
+	}
+}
+
+// LoadJWE creates a JWE object from a JWE string in the compact serialization.
+// The original encoded header is kept, as it is authenticated by the encryption.
+// Returns ErrBadJWETok if the string is not a valid JWE, ErrInvCrit if a crit (Critical) member is not supported,
+// ErrAlgNotImp if the zip (Compression) member is set or returns the JWE if everything was successful.
+func LoadJWE(token string) (*JWE, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 5 {
+		return nil, ErrBadJWETok
+	}
+	decoded := make([][]byte, len(parts))
+	for i, part := range parts {
+		var err error
+		decoded[i], err = base64.RawURLEncoding.DecodeString(part)
+		if err != nil {
+			return nil, ErrBadJWETok
+		}
+	}
+	res := &JWE{}
+	err := json.Unmarshal(decoded[0], &res.Header)
+	if err != nil {
+		return nil, ErrBadJWETok
+	}
+	err = res.Header.validateCritical()
+	if err != nil {
+		return nil, err
+	}
+	res.rawHeader = parts[0]
+	res.encryptedKey, res.iv, res.ciphertext, res.tag = decoded[1], decoded[2], decoded[3], decoded[4]
+	return res, nil
+}
+
+// IsEncrypted returns a bool, whether the JWE has been encrypted already or not.
+func (this *JWE) IsEncrypted() bool {
+	return len(this.ciphertext) != 0 || len(this.tag) != 0
+}
+
+// Encrypt encrypts a plaintext with the algorithms in the Header and a key, saved in the JWE.
+// RSA-OAEP and RSA-OAEP-256 require the public key, A128KW and A256KW require the key encryption key,
+// dir requires the content encryption key as a byte slice and ECDH-ES, ECDH-ES+A128KW and ECDH-ES+A256KW
+// require the *ecdsa.PublicKey or X25519PublicKey of the recipient. The key may also be a JWK or a BoundKey.
+// If the Header has no KeyID, it is set to the kid of a JWK or the RFC 7638 thumbprint (SHA-256) of an asymmetric key.
+// This method overwrites the previous contents of the JWE.
+// Returns ErrAlgNotImp if an algorithm in the Header is not implemented yet or the Compression is set,
+// ErrInvCrit if a Critical member is not supported,
+// ErrAlgNotAll if the key is not bound to the algorithm or ErrInvKeyTyp if the key can not be used with the algorithm.
+func (this *JWE) Encrypt(plaintext []byte, key interface{}) error {
+	management, content, err := this.algorithms()
+	if err != nil {
+		return err
+	}
+	err = this.Header.validateCritical()
+	if err != nil {
+		return err
+	}
+	resolved, err := resolveKey(this.Header.Algorithm, management.encryptOperation, key)
+	if err != nil {
+		return err
+	}
+	header := this.Header
+	if header.KeyID == "" {
+		header.KeyID = keyID(key)
+	}
+	cek, encryptedKey, err := management.encrypt(resolved, &header, content.keySize)
+	if err != nil {
+		return err
+	}
+	data, err := json.Marshal(header)
+	if err != nil {
+		return err
+	}
+	rawHeader := base64.RawURLEncoding.EncodeToString(data)
+	iv, ciphertext, tag, err := content.encrypt(cek, plaintext, []byte(rawHeader))
+	if err != nil {
+		return err
+	}
+	this.Header, this.rawHeader = header, rawHeader
+	this.encryptedKey, this.iv, this.ciphertext, this.tag = encryptedKey, iv, ciphertext, tag
+	return nil
+}
+
+// Decrypt decrypts the JWE with the algorithms in the Header and a key and returns the plaintext.
+// RSA-OAEP and RSA-OAEP-256 require the private key, A128KW and A256KW require the key encryption key,
+// dir requires the content encryption key as a byte slice and ECDH-ES, ECDH-ES+A128KW and ECDH-ES+A256KW
+// require the *ecdsa.PrivateKey or X25519PrivateKey. The key may also be a JWK or a BoundKey.
+// To restrict the algorithms a token may be decrypted with, the key can be bound to them with BindKey.
+// Returns ErrAlgNotImp if an algorithm in the Header is not implemented yet, ErrAlgNotAll if the key is
+// not bound to the algorithm, ErrInvKeyTyp if the key can not be used with the algorithm,
+// ErrTokNotEnc if the JWE has not been encrypted yet and ErrDecFail if the JWE can not be decrypted with the key.
+func (this *JWE) Decrypt(key interface{}) ([]byte, error) {
+	if !this.IsEncrypted() {
+		return nil, ErrTokNotEnc
+	}
+	management, content, err := this.algorithms()
+	if err != nil {
+		return nil, err
+	}
+	key, err = resolveKey(this.Header.Algorithm, management.decryptOperation, key)
+	if err != nil {
+		return nil, err
+	}
+	header := this.Header
+	cek, err := management.decrypt(key, &header, this.encryptedKey, content.keySize)
+	if err != nil {
+		return nil, err
+	}
+	return content.decrypt(cek, this.iv, this.ciphertext, this.tag, []byte(this.rawHeader))
+}
+
+// Parse formats the JWE into a JWE string in the compact serialization and returns the result.
+// It requires the JWE to be encrypted, otherwise it returns ErrTokNotEnc.
+// Result = Base64Encode(Header) + "." + Base64Encode(EncryptedKey) + "." + Base64Encode(IV) + "." +
+// Base64Encode(Ciphertext) + "." + Base64Encode(Tag)
+func (this *JWE) Parse() (string, error) {
+	if !this.IsEncrypted() {
+		return "", ErrTokNotEnc
+	}
+	return strings.Join(
+		[]string{
+			this.rawHeader,
+			base64.RawURLEncoding.EncodeToString(this.encryptedKey),
+			base64.RawURLEncoding.EncodeToString(this.iv),
+			base64.RawURLEncoding.EncodeToString(this.ciphertext),
+			base64.RawURLEncoding.EncodeToString(this.tag),
+		},
+		".",
+	), nil
+}
+
+// String formats the JWE into a JWE string and ignores probable errors.
+// To parse tokens in production environments, it is recommended to use the Parse method.
+func (this *JWE) String() string {
+	result, _ := this.Parse()
+	return result
+}
+
+// EncryptParse performs the Encrypt and Parse operations in one single step.
+func (this *JWE) EncryptParse(plaintext []byte, key interface{}) (string, error) {
+	err := this.Encrypt(plaintext, key)
+	if err != nil {
+		return "", err
+	}
+	return this.Parse()
+}
+
+// validateCritical checks, whether the crit (Critical) members are supported, as required by RFC 7516 section 4.1.13,
+// and whether the zip (Compression) member is set, which is not supported.
+func (this *JWEHeader) validateCritical() error {
+	if this.Critical != nil && len(this.Critical) == 0 {
+		return ErrInvCrit
+	}
+	for _, name := range this.Critical {
+		if !supportedJWECriticalHeaders[name] {
+			return ErrInvCrit
+		}
+	}
+	if this.Compression != "" {
+		return ErrAlgNotImp
+	}
+	return nil
+}
+
+func (this *JWE) algorithms() (keyManagement, contentEncryption, error) {
+	management, exists := keyManagementAlgorithms[this.Header.Algorithm]
+	if !exists {
+		return keyManagement{}, contentEncryption{}, ErrAlgNotImp
+	}
+	content, exists := contentEncryptionAlgorithms[this.Header.Encryption]
+	if !exists {
+		return keyManagement{}, contentEncryption{}, ErrAlgNotImp
+	}
+	return management, content, nil
+}
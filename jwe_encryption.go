@@ -0,0 +1,481 @@
+package gojwt
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/ecdsa"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/binary"
+	"golang.org/x/crypto/curve25519"
+	"hash"
+)
+
+var (
+	keyWrapIV = []byte{0xA6, 0xA6, 0xA6, 0xA6, 0xA6, 0xA6, 0xA6, 0xA6}
+)
+
+// keyManagement encrypts and decrypts the content encryption key of a JWE.
+type keyManagement struct {
+	encryptOperation string
+	decryptOperation string
+
+	// encrypt creates the content encryption key and returns it with the JWE Encrypted Key.
+	// It may set additional header members, which are integrity protected.
+	encrypt func(key interface{}, header *JWEHeader, keySize int) (cek, encryptedKey []byte, err error)
+
+	// decrypt returns the content encryption key of the JWE Encrypted Key.
+	decrypt func(key interface{}, header *JWEHeader, encryptedKey []byte, keySize int) (cek []byte, err error)
+}
+
+// contentEncryption encrypts and decrypts the plaintext of a JWE with the content encryption key.
+type contentEncryption struct {
+	keySize int
+	encrypt func(cek, plaintext, aad []byte) (iv, ciphertext, tag []byte, err error)
+	decrypt func(cek, iv, ciphertext, tag, aad []byte) (plaintext []byte, err error)
+}
+
+var (
+	keyManagementAlgorithms = map[string]keyManagement{
+		AlgRSAOAEP:    rsaOAEPKeyManagement(sha1.New),
+		AlgRSAOAEP256: rsaOAEPKeyManagement(sha256.New),
+		AlgA128KW:     aesKeyWrapKeyManagement(16),
+		AlgA256KW:     aesKeyWrapKeyManagement(32),
+		AlgDir: {
+			encryptOperation: "encrypt",
+			decryptOperation: "decrypt",
+			encrypt: func(key interface{}, header *JWEHeader, keySize int) ([]byte, []byte, error) {
+				cek, err := symmetricKey(key, keySize)
+				return cek, nil, err
+			},
+			decrypt: func(key interface{}, header *JWEHeader, encryptedKey []byte, keySize int) ([]byte, error) {
+				if len(encryptedKey) != 0 {
+					return nil, ErrBadJWETok
+				}
+				return symmetricKey(key, keySize)
+			},
+		},
+		AlgECDHES:       ecdhKeyManagement(0),
+		AlgECDHESA128KW: ecdhKeyManagement(16),
+		AlgECDHESA256KW: ecdhKeyManagement(32),
+	}
+	contentEncryptionAlgorithms = map[string]contentEncryption{
+		EncA128GCM:      {keySize: 16, encrypt: encryptGCM, decrypt: decryptGCM},
+		EncA256GCM:      {keySize: 32, encrypt: encryptGCM, decrypt: decryptGCM},
+		EncA128CBCHS256: {keySize: 32, encrypt: encryptCBCHS256, decrypt: decryptCBCHS256},
+	}
+)
+
+// WrapKeyAES wraps a key with a key encryption key using the AES Key Wrap algorithm specified in RFC 3394.
+// The key must be a multiple of 8 bytes and at least 16 bytes long.
+// Returns ErrInvKeyTyp if the key encryption key is not a valid AES key or the key has an invalid length.
+func WrapKeyAES(kek, key []byte) ([]byte, error) {
+	if len(key) < 16 || len(key)%8 != 0 {
+		return nil, ErrInvKeyTyp
+	}
+	block, err := aes.NewCipher(kek)
+	if err != nil {
+		return nil, ErrInvKeyTyp
+	}
+	n := len(key) / 8
+	r := make([]byte, len(key))
+	copy(r, key)
+	a := make([]byte, 8)
+	copy(a, keyWrapIV)
+	buffer := make([]byte, 16)
+	for j := 0; j < 6; j++ {
+		for i := 0; i < n; i++ {
+			copy(buffer, a)
+			copy(buffer[8:], r[i*8:i*8+8])
+			block.Encrypt(buffer, buffer)
+			t := uint64(n*j + i + 1)
+			binary.BigEndian.PutUint64(a, binary.BigEndian.Uint64(buffer[:8])^t)
+			copy(r[i*8:i*8+8], buffer[8:])
+		}
+	}
+	return append(a, r...), nil
+}
+
+// UnwrapKeyAES unwraps a key, which has been wrapped with a key encryption key using the AES Key Wrap algorithm
+// specified in RFC 3394. Returns ErrInvKeyTyp if the key encryption key is not a valid AES key
+// and ErrDecFail if the integrity check of the wrapped key fails.
+func UnwrapKeyAES(kek, wrapped []byte) ([]byte, error) {
+	block, err := aes.NewCipher(kek)
+	if err != nil {
+		return nil, ErrInvKeyTyp
+	}
+	if len(wrapped) < 24 || len(wrapped)%8 != 0 {
+		return nil, ErrDecFail
+	}
+	n := len(wrapped)/8 - 1
+	a := make([]byte, 8)
+	copy(a, wrapped[:8])
+	r := make([]byte, len(wrapped)-8)
+	copy(r, wrapped[8:])
+	buffer := make([]byte, 16)
+	for j := 5; j >= 0; j-- {
+		for i := n - 1; i >= 0; i-- {
+			t := uint64(n*j + i + 1)
+			binary.BigEndian.PutUint64(buffer, binary.BigEndian.Uint64(a)^t)
+			copy(buffer[8:], r[i*8:i*8+8])
+			block.Decrypt(buffer, buffer)
+			copy(a, buffer[:8])
+			copy(r[i*8:i*8+8], buffer[8:])
+		}
+	}
+	if subtle.ConstantTimeCompare(a, keyWrapIV) != 1 {
+		return nil, ErrDecFail
+	}
+	return r, nil
+}
+
+func rsaOAEPKeyManagement(hash func() hash.Hash) keyManagement {
+	return keyManagement{
+		encryptOperation: "wrapKey",
+		decryptOperation: "unwrapKey",
+		encrypt: func(key interface{}, header *JWEHeader, keySize int) ([]byte, []byte, error) {
+			var publicKey *rsa.PublicKey
+			switch k := key.(type) {
+			case *rsa.PublicKey:
+				publicKey = k
+			case *rsa.PrivateKey:
+				publicKey = &k.PublicKey
+			default:
+				return nil, nil, ErrInvKeyTyp
+			}
+			cek, err := randomBytes(keySize)
+			if err != nil {
+				return nil, nil, err
+			}
+			encryptedKey, err := rsa.EncryptOAEP(hash(), rand.Reader, publicKey, cek, nil)
+			return cek, encryptedKey, err
+		},
+		decrypt: func(key interface{}, header *JWEHeader, encryptedKey []byte, keySize int) ([]byte, error) {
+			privateKey, ok := key.(*rsa.PrivateKey)
+			if !ok {
+				return nil, ErrInvKeyTyp
+			}
+			cek, err := rsa.DecryptOAEP(hash(), rand.Reader, privateKey, encryptedKey, nil)
+			if err != nil || len(cek) != keySize {
+				// A random key is used on failure, so the failure can not be distinguished from
+				// an invalid authentication tag, as recommended by RFC 7516 section 11.5.
+				return randomBytes(keySize)
+			}
+			return cek, nil
+		},
+	}
+}
+
+func aesKeyWrapKeyManagement(kekSize int) keyManagement {
+	return keyManagement{
+		encryptOperation: "wrapKey",
+		decryptOperation: "unwrapKey",
+		encrypt: func(key interface{}, header *JWEHeader, keySize int) ([]byte, []byte, error) {
+			kek, err := symmetricKey(key, kekSize)
+			if err != nil {
+				return nil, nil, err
+			}
+			cek, err := randomBytes(keySize)
+			if err != nil {
+				return nil, nil, err
+			}
+			encryptedKey, err := WrapKeyAES(kek, cek)
+			return cek, encryptedKey, err
+		},
+		decrypt: func(key interface{}, header *JWEHeader, encryptedKey []byte, keySize int) ([]byte, error) {
+			kek, err := symmetricKey(key, kekSize)
+			if err != nil {
+				return nil, err
+			}
+			cek, err := UnwrapKeyAES(kek, encryptedKey)
+			if err != nil {
+				return nil, err
+			}
+			if len(cek) != keySize {
+				return nil, ErrDecFail
+			}
+			return cek, nil
+		},
+	}
+}
+
+// ecdhKeyManagement agrees on a key with ECDH-ES and derives the content encryption key from it
+// if kekSize is 0, or derives a key encryption key of kekSize bytes, which wraps the content encryption key.
+func ecdhKeyManagement(kekSize int) keyManagement {
+	return keyManagement{
+		encryptOperation: "deriveKey",
+		decryptOperation: "deriveKey",
+		encrypt: func(key interface{}, header *JWEHeader, keySize int) ([]byte, []byte, error) {
+			ephemeralKey, err := generateEphemeralKey(key)
+			if err != nil {
+				return nil, nil, err
+			}
+			z, err := agreeKey(ephemeralKey, key)
+			if err != nil {
+				return nil, nil, ErrInvKeyTyp
+			}
+			header.EphemeralPublicKey, err = (&JWK{Key: ephemeralKey}).Public()
+			if err != nil {
+				return nil, nil, err
+			}
+			if kekSize == 0 {
+				cek, err := concatKDF(z, header.Encryption, header, keySize)
+				return cek, nil, err
+			}
+			kek, err := concatKDF(z, header.Algorithm, header, kekSize)
+			if err != nil {
+				return nil, nil, err
+			}
+			cek, err := randomBytes(keySize)
+			if err != nil {
+				return nil, nil, err
+			}
+			encryptedKey, err := WrapKeyAES(kek, cek)
+			return cek, encryptedKey, err
+		},
+		decrypt: func(key interface{}, header *JWEHeader, encryptedKey []byte, keySize int) ([]byte, error) {
+			if header.EphemeralPublicKey == nil || header.EphemeralPublicKey.IsPrivate() {
+				return nil, ErrBadJWETok
+			}
+			z, err := agreeKey(key, header.EphemeralPublicKey.Key)
+			if err != nil {
+				return nil, err
+			}
+			if kekSize == 0 {
+				if len(encryptedKey) != 0 {
+					return nil, ErrBadJWETok
+				}
+				return concatKDF(z, header.Encryption, header, keySize)
+			}
+			kek, err := concatKDF(z, header.Algorithm, header, kekSize)
+			if err != nil {
+				return nil, err
+			}
+			cek, err := UnwrapKeyAES(kek, encryptedKey)
+			if err != nil {
+				return nil, err
+			}
+			if len(cek) != keySize {
+				return nil, ErrDecFail
+			}
+			return cek, nil
+		},
+	}
+}
+
+// generateEphemeralKey generates a private key on the curve of the public key of the recipient.
+func generateEphemeralKey(publicKey interface{}) (interface{}, error) {
+	switch k := publicKey.(type) {
+	case *ecdsa.PublicKey:
+		if curveName(k.Curve) == "" || !k.Curve.IsOnCurve(k.X, k.Y) {
+			return nil, ErrInvKeyTyp
+		}
+		return ecdsa.GenerateKey(k.Curve, rand.Reader)
+	case *ecdsa.PrivateKey:
+		return generateEphemeralKey(&k.PublicKey)
+	case X25519PublicKey:
+		return GenerateX25519Key()
+	case X25519PrivateKey:
+		return GenerateX25519Key()
+	}
+	return nil, ErrInvKeyTyp
+}
+
+// agreeKey computes the shared secret Z of a private key and a public key on the same curve.
+// Returns ErrInvKeyTyp if the private key is not supported and ErrDecFail if the public key does not match it.
+func agreeKey(privateKey, publicKey interface{}) ([]byte, error) {
+	if k, ok := publicKey.(*ecdsa.PrivateKey); ok {
+		publicKey = &k.PublicKey
+	}
+	if k, ok := publicKey.(X25519PrivateKey); ok {
+		publicKey = k.Public()
+	}
+	switch k := privateKey.(type) {
+	case *ecdsa.PrivateKey:
+		other, ok := publicKey.(*ecdsa.PublicKey)
+		if curveName(k.Curve) == "" {
+			return nil, ErrInvKeyTyp
+		}
+		if !ok || curveName(other.Curve) != curveName(k.Curve) || !k.Curve.IsOnCurve(other.X, other.Y) {
+			return nil, ErrDecFail
+		}
+		x, _ := k.Curve.ScalarMult(other.X, other.Y, k.D.Bytes())
+		if x.Sign() == 0 {
+			return nil, ErrDecFail
+		}
+		return x.FillBytes(make([]byte, (k.Curve.Params().BitSize+7)/8)), nil
+	case X25519PrivateKey:
+		other, ok := publicKey.(X25519PublicKey)
+		if len(k) != X25519KeySize {
+			return nil, ErrInvKeyTyp
+		}
+		if !ok || len(other) != X25519KeySize {
+			return nil, ErrDecFail
+		}
+		z, err := curve25519.X25519(k, other)
+		if err != nil {
+			return nil, ErrDecFail
+		}
+		return z, nil
+	}
+	return nil, ErrInvKeyTyp
+}
+
+// concatKDF derives a key of keySize bytes from the shared secret Z with the Concat KDF using SHA-256,
+// as specified in NIST SP 800-56A and RFC 7518 section 4.6.2.
+func concatKDF(z []byte, algorithmID string, header *JWEHeader, keySize int) ([]byte, error) {
+	apu, err := base64.RawURLEncoding.DecodeString(header.AgreementPartyUInfo)
+	if err != nil {
+		return nil, ErrBadJWETok
+	}
+	apv, err := base64.RawURLEncoding.DecodeString(header.AgreementPartyVInfo)
+	if err != nil {
+		return nil, ErrBadJWETok
+	}
+	var otherInfo []byte
+	for _, value := range [][]byte{[]byte(algorithmID), apu, apv} {
+		otherInfo = append(append(otherInfo, uint32Bytes(len(value))...), value...)
+	}
+	otherInfo = append(otherInfo, uint32Bytes(keySize*8)...)
+	res := make([]byte, 0, keySize+sha256.Size)
+	for counter := 1; len(res) < keySize; counter++ {
+		digest := sha256.New()
+		digest.Write(uint32Bytes(counter))
+		digest.Write(z)
+		digest.Write(otherInfo)
+		res = digest.Sum(res)
+	}
+	return res[:keySize], nil
+}
+
+func uint32Bytes(value int) []byte {
+	res := make([]byte, 4)
+	binary.BigEndian.PutUint32(res, uint32(value))
+	return res
+}
+
+func encryptGCM(cek, plaintext, aad []byte) ([]byte, []byte, []byte, error) {
+	aead, err := newGCM(cek)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	iv, err := randomBytes(aead.NonceSize())
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	sealed := aead.Seal(nil, iv, plaintext, aad)
+	split := len(sealed) - aead.Overhead()
+	return iv, sealed[:split], sealed[split:], nil
+}
+
+func decryptGCM(cek, iv, ciphertext, tag, aad []byte) ([]byte, error) {
+	aead, err := newGCM(cek)
+	if err != nil {
+		return nil, err
+	}
+	if len(iv) != aead.NonceSize() || len(tag) != aead.Overhead() {
+		return nil, ErrDecFail
+	}
+	sealed := make([]byte, 0, len(ciphertext)+len(tag))
+	sealed = append(append(sealed, ciphertext...), tag...)
+	plaintext, err := aead.Open(nil, iv, sealed, aad)
+	if err != nil {
+		return nil, ErrDecFail
+	}
+	return plaintext, nil
+}
+
+func newGCM(cek []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(cek)
+	if err != nil {
+		return nil, ErrInvKeyTyp
+	}
+	return cipher.NewGCM(block)
+}
+
+func encryptCBCHS256(cek, plaintext, aad []byte) ([]byte, []byte, []byte, error) {
+	macKey, encKey := cek[:16], cek[16:]
+	block, err := aes.NewCipher(encKey)
+	if err != nil {
+		return nil, nil, nil, ErrInvKeyTyp
+	}
+	iv, err := randomBytes(aes.BlockSize)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	padding := aes.BlockSize - len(plaintext)%aes.BlockSize
+	ciphertext := make([]byte, len(plaintext)+padding)
+	copy(ciphertext, plaintext)
+	for i := len(plaintext); i < len(ciphertext); i++ {
+		ciphertext[i] = byte(padding)
+	}
+	cipher.NewCBCEncrypter(block, iv).CryptBlocks(ciphertext, ciphertext)
+	return iv, ciphertext, cbcHS256Tag(macKey, aad, iv, ciphertext), nil
+}
+
+func decryptCBCHS256(cek, iv, ciphertext, tag, aad []byte) ([]byte, error) {
+	macKey, encKey := cek[:16], cek[16:]
+	block, err := aes.NewCipher(encKey)
+	if err != nil {
+		return nil, ErrInvKeyTyp
+	}
+	if len(iv) != aes.BlockSize || len(ciphertext) == 0 || len(ciphertext)%aes.BlockSize != 0 {
+		return nil, ErrDecFail
+	}
+	if !hmac.Equal(tag, cbcHS256Tag(macKey, aad, iv, ciphertext)) {
+		return nil, ErrDecFail
+	}
+	plaintext := make([]byte, len(ciphertext))
+	cipher.NewCBCDecrypter(block, iv).CryptBlocks(plaintext, ciphertext)
+	padding := int(plaintext[len(plaintext)-1])
+	if padding == 0 || padding > aes.BlockSize {
+		return nil, ErrDecFail
+	}
+	for _, b := range plaintext[len(plaintext)-padding:] {
+		if int(b) != padding {
+			return nil, ErrDecFail
+		}
+	}
+	return plaintext[:len(plaintext)-padding], nil
+}
+
+func cbcHS256Tag(macKey, aad, iv, ciphertext []byte) []byte {
+	length := make([]byte, 8)
+	binary.BigEndian.PutUint64(length, uint64(len(aad))*8)
+	mac := hmac.New(sha256.New, macKey)
+	mac.Write(aad)
+	mac.Write(iv)
+	mac.Write(ciphertext)
+	mac.Write(length)
+	return mac.Sum(nil)[:16]
+}
+
+func symmetricKey(key interface{}, size int) ([]byte, error) {
+	var res []byte
+	switch k := key.(type) {
+	case []byte:
+		res = k
+	case string:
+		res = []byte(k)
+	default:
+		return nil, ErrInvKeyTyp
+	}
+	if len(res) != size {
+		return nil, ErrInvKeyTyp
+	}
+	return res, nil
+}
+
+func randomBytes(size int) ([]byte, error) {
+	res := make([]byte, size)
+	_, err := rand.Read(res)
+	if err != nil {
+		return nil, err
+	}
+	return res, nil
+}
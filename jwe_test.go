@@ -0,0 +1,442 @@
+package gojwt_test
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"github.com/tobyguelly/gojwt"
+	"strings"
+	"testing"
+)
+
+func TestWrapKeyAES(t *testing.T) {
+	tests := []struct {
+		KEK            string
+		Key            string
+		ExpectedOutput string
+	}{
+		{
+			KEK:            "000102030405060708090A0B0C0D0E0F",
+			Key:            "00112233445566778899AABBCCDDEEFF",
+			ExpectedOutput: "1FA68B0A8112B447AEF34BD8FB5A7B829D3E862371D2CFE5",
+		},
+		{
+			KEK:            "000102030405060708090A0B0C0D0E0F101112131415161718191A1B1C1D1E1F",
+			Key:            "00112233445566778899AABBCCDDEEFF000102030405060708090A0B0C0D0E0F",
+			ExpectedOutput: "28C9F404C4B810F4CBCCB35CFB87F8263F5786E2D80ED326CBC7F0E71A99F43BFB988B9B7A02DD21",
+		},
+	}
+	for i, test := range tests {
+		kek, _ := hex.DecodeString(test.KEK)
+		key, _ := hex.DecodeString(test.Key)
+		wrapped, err := gojwt.WrapKeyAES(kek, key)
+		if err != nil {
+			t.Errorf("Failed test because of error: %s", err.Error())
+			continue
+		}
+		unwrapped, err := gojwt.UnwrapKeyAES(kek, wrapped)
+		if err != nil {
+			t.Errorf("Failed test because of error: %s", err.Error())
+			continue
+		}
+		res := strings.ToUpper(hex.EncodeToString(wrapped))
+		if res == test.ExpectedOutput && hex.EncodeToString(unwrapped) == strings.ToLower(test.Key) {
+			t.Logf("Passed %d/%d tests!", i+1, len(tests))
+		} else {
+			t.Errorf("Output and expected output did not match: %s\nFound:\t\t%s\nExpected:\t%s",
+				test.Key, res, test.ExpectedOutput,
+			)
+		}
+	}
+}
+
+func TestLoadJWE_Decrypt(t *testing.T) {
+	// The example of RFC 7516 appendix A.3, using A128KW and A128CBC-HS256.
+	token := "eyJhbGciOiJBMTI4S1ciLCJlbmMiOiJBMTI4Q0JDLUhTMjU2In0." +
+		"6KB707dM9YTIgHtLvtgWQ8mKwboJW3of9locizkDTHzBC2IlrT1oOQ." +
+		"AxY8DCtDaGlsbGljb3RoZQ." +
+		"KDlTtXchhZTGufMYmOYGS4HffxPSUrfmqCHXaI9wOGY." +
+		"U0m_YmjN04DJvceFICbCVQ"
+	key, err := gojwt.LoadJWK([]byte(`{"kty":"oct","k":"GawgguFyGrWKav7AX4VKUg"}`))
+	if err != nil {
+		t.Errorf("Failed test because of error: %s", err.Error())
+		t.FailNow()
+	}
+	// The key agreement of RFC 7518 appendix C, using ECDH-ES and A128GCM, which derives the key
+	// VqqN6vgjbSBcIijNcacQGg from the ephemeral key of Alice and the static key of Bob.
+	ecdhHeader := base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"ECDH-ES","enc":"A128GCM","apu":"QWxpY2U","apv":"Qm9i",` +
+		`"epk":{"kty":"EC","crv":"P-256","x":"gI0GAILBdu7T53akrFmMyGcsF3n5dO7MmwNBHKW5SV0","y":"SLW_xSffzlPWrHEVI30DHM_4egVwt3NQqeUD7nMFpps"}}`,
+	))
+	cek, _ := base64.RawURLEncoding.DecodeString("VqqN6vgjbSBcIijNcacQGg")
+	block, err := aes.NewCipher(cek)
+	if err != nil {
+		t.Errorf("Failed test because of error: %s", err.Error())
+		t.FailNow()
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		t.Errorf("Failed test because of error: %s", err.Error())
+		t.FailNow()
+	}
+	iv := make([]byte, aead.NonceSize())
+	sealed := aead.Seal(nil, iv, []byte("Live long and prosper."), []byte(ecdhHeader))
+	split := len(sealed) - aead.Overhead()
+	ecdhToken := ecdhHeader + ".." + base64.RawURLEncoding.EncodeToString(iv) + "." +
+		base64.RawURLEncoding.EncodeToString(sealed[:split]) + "." + base64.RawURLEncoding.EncodeToString(sealed[split:])
+	bob, err := gojwt.LoadJWK([]byte(`{"kty":"EC","crv":"P-256","x":"weNJy2HscCSM6AEDTDg04biOvhFhyyWvOHQfeF_PxMQ",` +
+		`"y":"e8lnCO-AlStT-NJVX-crhB7QRYhiix03illJOVAOyck","d":"VEmDZpDXXK8p8N0Cndsxs924q6nS1RXFASRl6BfUqdw"}`,
+	))
+	if err != nil {
+		t.Errorf("Failed test because of error: %s", err.Error())
+		t.FailNow()
+	}
+	alice, err := gojwt.LoadJWK([]byte(`{"kty":"EC","crv":"P-256","x":"gI0GAILBdu7T53akrFmMyGcsF3n5dO7MmwNBHKW5SV0",` +
+		`"y":"SLW_xSffzlPWrHEVI30DHM_4egVwt3NQqeUD7nMFpps","d":"0_NxaRPUMQoAJt50Gz8YiTr8gRTwyEaCumd-MToTmIo"}`,
+	))
+	if err != nil {
+		t.Errorf("Failed test because of error: %s", err.Error())
+		t.FailNow()
+	}
+	withHeader := func(header string) string {
+		return base64.RawURLEncoding.EncodeToString([]byte(header)) + token[strings.Index(token, "."):]
+	}
+	tests := []struct {
+		Input          string
+		Key            interface{}
+		ExpectedOutput string
+		ExpectedError  error
+	}{
+		{
+			Input:          token,
+			Key:            key,
+			ExpectedOutput: "Live long and prosper.",
+			ExpectedError:  nil,
+		},
+		{
+			Input:         token,
+			Key:           []byte("0123456789abcdef"),
+			ExpectedError: gojwt.ErrDecFail,
+		},
+		{
+			Input:         token,
+			Key:           gojwt.BindKey(key, gojwt.AlgRSAOAEP),
+			ExpectedError: gojwt.ErrAlgNotAll,
+		},
+		{
+			Input:         strings.Replace(token, ".U0m_", ".U1m_", 1),
+			Key:           key,
+			ExpectedError: gojwt.ErrDecFail,
+		},
+		{
+			Input:         strings.Replace(token, "eyJhbGciOiJBMTI4S1ciLCJlbmMiOiJBMTI4Q0JDLUhTMjU2In0", "eyJlbmMiOiJBMTI4Q0JDLUhTMjU2IiwiYWxnIjoiQTEyOEtXIn0", 1),
+			Key:           key,
+			ExpectedError: gojwt.ErrDecFail,
+		},
+		{
+			Input:          ecdhToken,
+			Key:            bob,
+			ExpectedOutput: "Live long and prosper.",
+			ExpectedError:  nil,
+		},
+		{
+			Input:         ecdhToken,
+			Key:           alice,
+			ExpectedError: gojwt.ErrDecFail,
+		},
+		{
+			Input:         ecdhToken,
+			Key:           key,
+			ExpectedError: gojwt.ErrInvKeyTyp,
+		},
+		{
+			Input:         token[:strings.LastIndex(token, ".")],
+			Key:           key,
+			ExpectedError: gojwt.ErrBadJWETok,
+		},
+		{
+			Input:         withHeader(`{"alg":"A128KW","enc":"A128CBC-HS256","crit":["exp"],"exp":1363284000}`),
+			Key:           key,
+			ExpectedError: gojwt.ErrInvCrit,
+		},
+		{
+			Input:         withHeader(`{"alg":"A128KW","enc":"A128CBC-HS256","crit":[]}`),
+			Key:           key,
+			ExpectedError: gojwt.ErrInvCrit,
+		},
+		{
+			Input:         withHeader(`{"alg":"A128KW","enc":"A128CBC-HS256","zip":"DEF"}`),
+			Key:           key,
+			ExpectedError: gojwt.ErrAlgNotImp,
+		},
+	}
+	for i, test := range tests {
+		jwe, err := gojwt.LoadJWE(test.Input)
+		var res []byte
+		if err == nil {
+			res, err = jwe.Decrypt(test.Key)
+		}
+		if errors.Is(err, test.ExpectedError) && string(res) == test.ExpectedOutput {
+			t.Logf("Passed %d/%d tests!", i+1, len(tests))
+		} else {
+			t.Errorf("Output and expected output did not match: %s\nFound:\t\t%s %v\nExpected:\t%s %v",
+				test.Input, res, err, test.ExpectedOutput, test.ExpectedError,
+			)
+		}
+	}
+}
+
+func TestJWE_EncryptAndDecrypt(t *testing.T) {
+	privateKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Errorf("Failed test because of error: %s", err.Error())
+		t.FailNow()
+	}
+	otherKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Errorf("Failed test because of error: %s", err.Error())
+		t.FailNow()
+	}
+	p256Key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Errorf("Failed test because of error: %s", err.Error())
+		t.FailNow()
+	}
+	p384Key, err := ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+	if err != nil {
+		t.Errorf("Failed test because of error: %s", err.Error())
+		t.FailNow()
+	}
+	p521Key, err := ecdsa.GenerateKey(elliptic.P521(), rand.Reader)
+	if err != nil {
+		t.Errorf("Failed test because of error: %s", err.Error())
+		t.FailNow()
+	}
+	x25519Key, err := gojwt.GenerateX25519Key()
+	if err != nil {
+		t.Errorf("Failed test because of error: %s", err.Error())
+		t.FailNow()
+	}
+	otherX25519Key, err := gojwt.GenerateX25519Key()
+	if err != nil {
+		t.Errorf("Failed test because of error: %s", err.Error())
+		t.FailNow()
+	}
+	secret16 := []byte("0123456789abcdef")
+	secret32 := []byte("0123456789abcdef0123456789abcdef")
+	tests := []struct {
+		Algorithm     string
+		Encryption    string
+		EncryptionKey interface{}
+		DecryptionKey interface{}
+		ExpectedError error
+	}{
+		{
+			Algorithm:     gojwt.AlgRSAOAEP,
+			Encryption:    gojwt.EncA256GCM,
+			EncryptionKey: &privateKey.PublicKey,
+			DecryptionKey: privateKey,
+			ExpectedError: nil,
+		},
+		{
+			Algorithm:     gojwt.AlgRSAOAEP256,
+			Encryption:    gojwt.EncA128GCM,
+			EncryptionKey: &privateKey.PublicKey,
+			DecryptionKey: privateKey,
+			ExpectedError: nil,
+		},
+		{
+			Algorithm:     gojwt.AlgRSAOAEP256,
+			Encryption:    gojwt.EncA128CBCHS256,
+			EncryptionKey: &privateKey.PublicKey,
+			DecryptionKey: privateKey,
+			ExpectedError: nil,
+		},
+		{
+			Algorithm:     gojwt.AlgA128KW,
+			Encryption:    gojwt.EncA128GCM,
+			EncryptionKey: secret16,
+			DecryptionKey: secret16,
+			ExpectedError: nil,
+		},
+		{
+			Algorithm:     gojwt.AlgA256KW,
+			Encryption:    gojwt.EncA128CBCHS256,
+			EncryptionKey: secret32,
+			DecryptionKey: secret32,
+			ExpectedError: nil,
+		},
+		{
+			Algorithm:     gojwt.AlgDir,
+			Encryption:    gojwt.EncA256GCM,
+			EncryptionKey: secret32,
+			DecryptionKey: secret32,
+			ExpectedError: nil,
+		},
+		{
+			Algorithm:     gojwt.AlgDir,
+			Encryption:    gojwt.EncA128GCM,
+			EncryptionKey: secret16,
+			DecryptionKey: &gojwt.JWK{Key: secret16, Use: gojwt.UseEnc},
+			ExpectedError: nil,
+		},
+		{
+			Algorithm:     gojwt.AlgECDHES,
+			Encryption:    gojwt.EncA128GCM,
+			EncryptionKey: &p256Key.PublicKey,
+			DecryptionKey: p256Key,
+			ExpectedError: nil,
+		},
+		{
+			Algorithm:     gojwt.AlgECDHES,
+			Encryption:    gojwt.EncA128CBCHS256,
+			EncryptionKey: &p521Key.PublicKey,
+			DecryptionKey: p521Key,
+			ExpectedError: nil,
+		},
+		{
+			Algorithm:     gojwt.AlgECDHESA128KW,
+			Encryption:    gojwt.EncA256GCM,
+			EncryptionKey: &p384Key.PublicKey,
+			DecryptionKey: p384Key,
+			ExpectedError: nil,
+		},
+		{
+			Algorithm:     gojwt.AlgECDHESA256KW,
+			Encryption:    gojwt.EncA128GCM,
+			EncryptionKey: &gojwt.JWK{Key: &p521Key.PublicKey, Use: gojwt.UseEnc},
+			DecryptionKey: &gojwt.JWK{Key: p521Key, KeyOperations: []string{"deriveKey"}},
+			ExpectedError: nil,
+		},
+		{
+			Algorithm:     gojwt.AlgECDHES,
+			Encryption:    gojwt.EncA256GCM,
+			EncryptionKey: x25519Key.Public(),
+			DecryptionKey: x25519Key,
+			ExpectedError: nil,
+		},
+		{
+			Algorithm:     gojwt.AlgECDHESA256KW,
+			Encryption:    gojwt.EncA128CBCHS256,
+			EncryptionKey: x25519Key.Public(),
+			DecryptionKey: x25519Key,
+			ExpectedError: nil,
+		},
+		{
+			Algorithm:     gojwt.AlgECDHESA128KW,
+			Encryption:    gojwt.EncA128GCM,
+			EncryptionKey: x25519Key.Public(),
+			DecryptionKey: otherX25519Key,
+			ExpectedError: gojwt.ErrDecFail,
+		},
+		{
+			Algorithm:     gojwt.AlgECDHES,
+			Encryption:    gojwt.EncA128GCM,
+			EncryptionKey: &p256Key.PublicKey,
+			DecryptionKey: p384Key,
+			ExpectedError: gojwt.ErrDecFail,
+		},
+		{
+			Algorithm:     gojwt.AlgECDHES,
+			Encryption:    gojwt.EncA128GCM,
+			EncryptionKey: &p256Key.PublicKey,
+			DecryptionKey: &p256Key.PublicKey,
+			ExpectedError: gojwt.ErrInvKeyTyp,
+		},
+		{
+			Algorithm:     gojwt.AlgECDHES,
+			Encryption:    gojwt.EncA128GCM,
+			EncryptionKey: &privateKey.PublicKey,
+			DecryptionKey: privateKey,
+			ExpectedError: gojwt.ErrInvKeyTyp,
+		},
+		{
+			Algorithm:     gojwt.AlgECDHESA128KW,
+			Encryption:    gojwt.EncA128GCM,
+			EncryptionKey: &gojwt.JWK{Key: &p256Key.PublicKey, Use: gojwt.UseSig},
+			DecryptionKey: p256Key,
+			ExpectedError: gojwt.ErrInvKeyUse,
+		},
+		{
+			Algorithm:     gojwt.AlgRSAOAEP,
+			Encryption:    gojwt.EncA256GCM,
+			EncryptionKey: &privateKey.PublicKey,
+			DecryptionKey: otherKey,
+			ExpectedError: gojwt.ErrDecFail,
+		},
+		{
+			Algorithm:     gojwt.AlgRSAOAEP,
+			Encryption:    gojwt.EncA256GCM,
+			EncryptionKey: &privateKey.PublicKey,
+			DecryptionKey: &privateKey.PublicKey,
+			ExpectedError: gojwt.ErrInvKeyTyp,
+		},
+		{
+			Algorithm:     gojwt.AlgA128KW,
+			Encryption:    gojwt.EncA128GCM,
+			EncryptionKey: secret16,
+			DecryptionKey: []byte("fedcba9876543210"),
+			ExpectedError: gojwt.ErrDecFail,
+		},
+		{
+			Algorithm:     gojwt.AlgA128KW,
+			Encryption:    gojwt.EncA128GCM,
+			EncryptionKey: secret32,
+			DecryptionKey: secret32,
+			ExpectedError: gojwt.ErrInvKeyTyp,
+		},
+		{
+			Algorithm:     gojwt.AlgDir,
+			Encryption:    gojwt.EncA128CBCHS256,
+			EncryptionKey: secret16,
+			DecryptionKey: secret16,
+			ExpectedError: gojwt.ErrInvKeyTyp,
+		},
+		{
+			Algorithm:     gojwt.AlgDir,
+			Encryption:    gojwt.EncA128GCM,
+			EncryptionKey: secret16,
+			DecryptionKey: &gojwt.JWK{Key: secret16, Use: gojwt.UseSig},
+			ExpectedError: gojwt.ErrInvKeyUse,
+		},
+		{
+			Algorithm:     gojwt.AlgDir,
+			Encryption:    "A192GCM",
+			EncryptionKey: secret16,
+			DecryptionKey: secret16,
+			ExpectedError: gojwt.ErrAlgNotImp,
+		},
+		{
+			Algorithm:     "RSA1_5",
+			Encryption:    gojwt.EncA128GCM,
+			EncryptionKey: privateKey,
+			DecryptionKey: privateKey,
+			ExpectedError: gojwt.ErrAlgNotImp,
+		},
+	}
+	plaintext := []byte("Live long and prosper.")
+	for i, test := range tests {
+		jwe := gojwt.NewJWE(test.Algorithm, test.Encryption)
+		token, err := jwe.EncryptParse(plaintext, test.EncryptionKey)
+		var res []byte
+		if err == nil {
+			var loaded *gojwt.JWE
+			loaded, err = gojwt.LoadJWE(token)
+			if err == nil {
+				res, err = loaded.Decrypt(test.DecryptionKey)
+			}
+		}
+		if errors.Is(err, test.ExpectedError) && (err != nil || string(res) == string(plaintext)) {
+			t.Logf("Passed %d/%d tests!", i+1, len(tests))
+		} else {
+			t.Errorf("Output and expected output did not match: %s/%s\nFound:\t\t%s %v\nExpected:\t%s %v",
+				test.Algorithm, test.Encryption, res, err, plaintext, test.ExpectedError,
+			)
+		}
+	}
+}
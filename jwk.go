@@ -0,0 +1,677 @@
+package gojwt
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"strings"
+)
+
+var (
+	publicKeyOperations = map[string]string{
+		"sign":       "verify",
+		"verify":     "verify",
+		"decrypt":    "encrypt",
+		"encrypt":    "encrypt",
+		"unwrapKey":  "wrapKey",
+		"wrapKey":    "wrapKey",
+		"deriveKey":  "deriveKey",
+		"deriveBits": "deriveBits",
+	}
+	signatureKeyOperations = map[string]bool{
+		"sign":   true,
+		"verify": true,
+	}
+	algorithmKeyTypes = map[string][]string{
+		AlgHS256: {KtyOct},
+		AlgHS384: {KtyOct},
+		AlgHS512: {KtyOct},
+		AlgRS256: {KtyRSA},
+		AlgRS384: {KtyRSA},
+		AlgRS512: {KtyRSA},
+		AlgPS256: {KtyRSA},
+		AlgPS384: {KtyRSA},
+		AlgPS512: {KtyRSA},
+		AlgES256: {KtyEC},
+		AlgES384: {KtyEC},
+		AlgES512: {KtyEC},
+		AlgEdDSA: {KtyOKP},
+
+		AlgRSAOAEP:    {KtyRSA},
+		AlgRSAOAEP256: {KtyRSA},
+		AlgA128KW:     {KtyOct},
+		AlgA256KW:     {KtyOct},
+		AlgDir:        {KtyOct},
+
+		AlgECDHES:       {KtyEC, KtyOKP},
+		AlgECDHESA128KW: {KtyEC, KtyOKP},
+		AlgECDHESA256KW: {KtyEC, KtyOKP},
+	}
+	algorithmCurves = map[string]string{
+		AlgES256: CrvP256,
+		AlgES384: CrvP384,
+		AlgES512: CrvP521,
+		AlgEdDSA: CrvEd25519,
+	}
+)
+
+// JWK is a JSON Web Key as specified in RFC 7517, which wraps a key of the crypto packages
+// of the standard library. It implements the json.Marshaler and json.Unmarshaler interface.
+type JWK struct {
+
+	// Key is the key, which is one of *rsa.PublicKey, *rsa.PrivateKey, *ecdsa.PublicKey, *ecdsa.PrivateKey,
+	// ed25519.PublicKey, ed25519.PrivateKey, X25519PublicKey, X25519PrivateKey or a byte slice for symmetric keys.
+	Key interface{}
+
+	// KeyID is the kid member, which identifies the key.
+	KeyID string
+
+	// Use is the use member, which is either UseSig or UseEnc.
+	Use string
+
+	// KeyOperations is the key_ops member, which holds the operations the key may be used for.
+	KeyOperations []string
+
+	// Algorithm is the alg member, which identifies the algorithm the key may be used with.
+	Algorithm string
+}
+
+type jwkJSON struct {
+	KeyType       string   `json:"kty"`
+	KeyID         string   `json:"kid,omitempty"`
+	Use           string   `json:"use,omitempty"`
+	KeyOperations []string `json:"key_ops,omitempty"`
+	Algorithm     string   `json:"alg,omitempty"`
+	Curve         string   `json:"crv,omitempty"`
+	N             string   `json:"n,omitempty"`
+	E             string   `json:"e,omitempty"`
+	X             string   `json:"x,omitempty"`
+	Y             string   `json:"y,omitempty"`
+	D             string   `json:"d,omitempty"`
+	P             string   `json:"p,omitempty"`
+	Q             string   `json:"q,omitempty"`
+	DP            string   `json:"dp,omitempty"`
+	DQ            string   `json:"dq,omitempty"`
+	QI            string   `json:"qi,omitempty"`
+	K             string   `json:"k,omitempty"`
+}
+
+// NewJWK creates a JWK from a key. Secrets can be passed as a string or a byte slice.
+// Returns ErrInvKeyTyp if the key type is not supported.
+func NewJWK(key interface{}) (*JWK, error) {
+	if isNilKey(key) {
+		return nil, ErrInvKeyTyp
+	}
+	if secret, ok := key.(string); ok {
+		key = []byte(secret)
+	}
+	jwk := &JWK{Key: key}
+	if _, err := jwk.toJSON(); err != nil {
+		return nil, err
+	}
+	return jwk, nil
+}
+
+// LoadJWK creates a JWK from its JSON representation.
+// Returns an error wrapping ErrInvJWK if the JWK is malformed or inconsistent.
+func LoadJWK(data []byte) (*JWK, error) {
+	jwk := &JWK{}
+	err := json.Unmarshal(data, jwk)
+	if err != nil {
+		return nil, err
+	}
+	return jwk, nil
+}
+
+// KeyType returns the kty member of the JWK, which is one of KtyRSA, KtyEC, KtyOKP or KtyOct.
+func (this *JWK) KeyType() string {
+	kty, _ := keyType(this.Key)
+	return kty
+}
+
+// Curve returns the crv member of the JWK, which is empty for RSA and symmetric keys.
+func (this *JWK) Curve() string {
+	_, crv := keyType(this.Key)
+	return crv
+}
+
+// IsPrivate returns a bool, whether the JWK holds a private or symmetric key.
+func (this *JWK) IsPrivate() bool {
+	switch this.Key.(type) {
+	case *rsa.PrivateKey, *ecdsa.PrivateKey, ed25519.PrivateKey, X25519PrivateKey, []byte:
+		return true
+	}
+	return false
+}
+
+// Public returns a copy of the JWK holding the public key, without any private members.
+// Operations of the key_ops member, which require the private key, are replaced with their public counterparts,
+// like "sign" with "verify", "decrypt" with "encrypt" and "unwrapKey" with "wrapKey".
+// Returns ErrInvKeyTyp for symmetric keys, which do not have a public key,
+// or ErrInvKeyUse if no operation of the key_ops member can be performed with the public key.
+func (this *JWK) Public() (*JWK, error) {
+	var key interface{}
+	switch k := this.Key.(type) {
+	case *rsa.PrivateKey:
+		key = &k.PublicKey
+	case *ecdsa.PrivateKey:
+		key = &k.PublicKey
+	case ed25519.PrivateKey:
+		key = k.Public()
+	case X25519PrivateKey:
+		key = k.Public()
+	case *rsa.PublicKey, *ecdsa.PublicKey, ed25519.PublicKey, X25519PublicKey:
+		key = k
+	default:
+		return nil, ErrInvKeyTyp
+	}
+	var operations []string
+	for _, operation := range this.KeyOperations {
+		public, ok := publicKeyOperations[operation]
+		if ok && !contains(operations, public) {
+			operations = append(operations, public)
+		}
+	}
+	if len(this.KeyOperations) > 0 && len(operations) == 0 {
+		return nil, ErrInvKeyUse
+	}
+	return &JWK{
+		Key:           key,
+		KeyID:         this.KeyID,
+		Use:           this.Use,
+		KeyOperations: operations,
+		Algorithm:     this.Algorithm,
+	}, nil
+}
+
+// Allows returns a bool, whether the JWK may be used with an algorithm and for an operation,
+// like "sign" or "verify", based on its alg, use and key_ops members.
+func (this *JWK) Allows(alg, operation string) bool {
+	if this.Algorithm != "" && this.Algorithm != alg {
+		return false
+	}
+	if this.Use != "" && (this.Use == UseSig) != signatureKeyOperations[operation] {
+		return false
+	}
+	return len(this.KeyOperations) == 0 || contains(this.KeyOperations, operation)
+}
+
+// Thumbprint computes the JWK Thumbprint as specified in RFC 7638, which is the base64 rawURLEncoded hash
+// of the required public members of the key. Private and public keys have the same thumbprint.
+// Returns ErrAlgNotImp if the hash function is not available.
+func (this *JWK) Thumbprint(hash crypto.Hash) (string, error) {
+	if !hash.Available() {
+		return "", ErrAlgNotImp
+	}
+	res, err := this.toJSON()
+	if err != nil {
+		return "", err
+	}
+	members := map[string]string{
+		"kty": res.KeyType,
+	}
+	switch res.KeyType {
+	case KtyRSA:
+		members["e"], members["n"] = res.E, res.N
+	case KtyEC:
+		members["crv"], members["x"], members["y"] = res.Curve, res.X, res.Y
+	case KtyOKP:
+		members["crv"], members["x"] = res.Curve, res.X
+	case KtyOct:
+		members["k"] = res.K
+	}
+	// The members are marshalled in lexicographic order without whitespace, as required by RFC 7638.
+	data, err := json.Marshal(members)
+	if err != nil {
+		return "", err
+	}
+	digest := hash.New()
+	digest.Write(data)
+	return base64.RawURLEncoding.EncodeToString(digest.Sum(nil)), nil
+}
+
+// MarshalJSON is the implementation of the json.Marshaler interface.
+// Returns an error wrapping ErrInvJWK if the members of the JWK are inconsistent.
+func (this JWK) MarshalJSON() ([]byte, error) {
+	res, err := this.toJSON()
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(res)
+}
+
+// UnmarshalJSON is the implementation of the json.Unmarshaler interface.
+// Returns an error wrapping ErrInvJWK if the JWK is malformed or its members are inconsistent.
+func (this *JWK) UnmarshalJSON(data []byte) error {
+	var raw jwkJSON
+	err := json.Unmarshal(data, &raw)
+	if err != nil {
+		return fmt.Errorf("%w: %s", ErrInvJWK, err.Error())
+	}
+	jwk := JWK{
+		KeyID:         raw.KeyID,
+		Use:           raw.Use,
+		KeyOperations: raw.KeyOperations,
+		Algorithm:     raw.Algorithm,
+	}
+	switch raw.KeyType {
+	case KtyRSA:
+		jwk.Key, err = raw.rsaKey()
+	case KtyEC:
+		jwk.Key, err = raw.ecKey()
+	case KtyOKP:
+		jwk.Key, err = raw.okpKey()
+	case KtyOct:
+		jwk.Key, err = raw.octKey()
+	case "":
+		err = invalidJWK("missing kty member")
+	default:
+		err = invalidJWK("unsupported kty %q", raw.KeyType)
+	}
+	if err != nil {
+		return err
+	}
+	err = jwk.validateMembers(raw.KeyType, raw.Curve)
+	if err != nil {
+		return err
+	}
+	*this = jwk
+	return nil
+}
+
+func (this *JWK) toJSON() (*jwkJSON, error) {
+	res := &jwkJSON{
+		KeyID:         this.KeyID,
+		Use:           this.Use,
+		KeyOperations: this.KeyOperations,
+		Algorithm:     this.Algorithm,
+	}
+	switch k := this.Key.(type) {
+	case *rsa.PublicKey:
+		res.setRSAPublic(k)
+	case *rsa.PrivateKey:
+		if len(k.Primes) != 2 {
+			return nil, invalidJWK("RSA keys must have exactly two primes")
+		}
+		p, q, one := k.Primes[0], k.Primes[1], big.NewInt(1)
+		res.setRSAPublic(&k.PublicKey)
+		res.D = encodeBigInt(k.D, 0)
+		res.P = encodeBigInt(p, 0)
+		res.Q = encodeBigInt(q, 0)
+		res.DP = encodeBigInt(new(big.Int).Mod(k.D, new(big.Int).Sub(p, one)), 0)
+		res.DQ = encodeBigInt(new(big.Int).Mod(k.D, new(big.Int).Sub(q, one)), 0)
+		res.QI = encodeBigInt(new(big.Int).ModInverse(q, p), 0)
+	case *ecdsa.PublicKey:
+		err := res.setECPublic(k)
+		if err != nil {
+			return nil, err
+		}
+	case *ecdsa.PrivateKey:
+		err := res.setECPublic(&k.PublicKey)
+		if err != nil {
+			return nil, err
+		}
+		res.D = encodeBigInt(k.D, (k.Curve.Params().BitSize+7)/8)
+	case ed25519.PublicKey:
+		if len(k) != ed25519.PublicKeySize {
+			return nil, ErrInvKeyTyp
+		}
+		res.KeyType, res.Curve = KtyOKP, CrvEd25519
+		res.X = base64.RawURLEncoding.EncodeToString(k)
+	case ed25519.PrivateKey:
+		if len(k) != ed25519.PrivateKeySize {
+			return nil, ErrInvKeyTyp
+		}
+		res.KeyType, res.Curve = KtyOKP, CrvEd25519
+		res.X = base64.RawURLEncoding.EncodeToString(k[ed25519.SeedSize:])
+		res.D = base64.RawURLEncoding.EncodeToString(k.Seed())
+	case X25519PublicKey:
+		if len(k) != X25519KeySize {
+			return nil, ErrInvKeyTyp
+		}
+		res.KeyType, res.Curve = KtyOKP, CrvX25519
+		res.X = base64.RawURLEncoding.EncodeToString(k)
+	case X25519PrivateKey:
+		publicKey, ok := k.Public().(X25519PublicKey)
+		if len(k) != X25519KeySize || !ok {
+			return nil, ErrInvKeyTyp
+		}
+		res.KeyType, res.Curve = KtyOKP, CrvX25519
+		res.X = base64.RawURLEncoding.EncodeToString(publicKey)
+		res.D = base64.RawURLEncoding.EncodeToString(k)
+	case []byte:
+		if len(k) == 0 {
+			return nil, ErrInvKeyTyp
+		}
+		res.KeyType = KtyOct
+		res.K = base64.RawURLEncoding.EncodeToString(k)
+	default:
+		return nil, ErrInvKeyTyp
+	}
+	err := this.validateMembers(res.KeyType, res.Curve)
+	if err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+func (this *JWK) validateMembers(kty, crv string) error {
+	if expected, exists := algorithmKeyTypes[this.Algorithm]; exists {
+		if !contains(expected, kty) {
+			return invalidJWK("alg %q can not be used with kty %q", this.Algorithm, kty)
+		}
+		if curve, exists := algorithmCurves[this.Algorithm]; exists && curve != crv {
+			return invalidJWK("alg %q can not be used with crv %q", this.Algorithm, crv)
+		}
+	}
+	seen := make(map[string]bool)
+	for _, operation := range this.KeyOperations {
+		if seen[operation] {
+			return invalidJWK("duplicate key_ops value %q", operation)
+		}
+		seen[operation] = true
+		if this.Use != "" && (this.Use == UseSig) != signatureKeyOperations[operation] {
+			return invalidJWK("key_ops value %q is inconsistent with use %q", operation, this.Use)
+		}
+	}
+	return nil
+}
+
+func (this *jwkJSON) setRSAPublic(key *rsa.PublicKey) {
+	this.KeyType = KtyRSA
+	this.N = encodeBigInt(key.N, 0)
+	this.E = encodeBigInt(big.NewInt(int64(key.E)), 0)
+}
+
+func (this *jwkJSON) setECPublic(key *ecdsa.PublicKey) error {
+	crv := curveName(key.Curve)
+	if crv == "" {
+		return ErrInvKeyCrv
+	}
+	size := (key.Curve.Params().BitSize + 7) / 8
+	this.KeyType, this.Curve = KtyEC, crv
+	this.X = encodeBigInt(key.X, size)
+	this.Y = encodeBigInt(key.Y, size)
+	return nil
+}
+
+func (this *jwkJSON) rsaKey() (interface{}, error) {
+	if this.Curve != "" || this.X != "" || this.Y != "" || this.K != "" {
+		return nil, invalidJWK("RSA key with members of other key types")
+	}
+	n, err := decodeMember("n", this.N, true)
+	if err != nil {
+		return nil, err
+	}
+	e, err := decodeMember("e", this.E, true)
+	if err != nil {
+		return nil, err
+	}
+	if len(e) > 4 || new(big.Int).SetBytes(e).Int64() < 2 {
+		return nil, invalidJWK("invalid RSA exponent")
+	}
+	publicKey := rsa.PublicKey{
+		N: new(big.Int).SetBytes(n),
+		E: int(new(big.Int).SetBytes(e).Int64()),
+	}
+	if this.D == "" {
+		if this.P != "" || this.Q != "" || this.DP != "" || this.DQ != "" || this.QI != "" {
+			return nil, invalidJWK("RSA public key with private members")
+		}
+		return &publicKey, nil
+	}
+	members := make(map[string]*big.Int)
+	for _, member := range []struct {
+		Name     string
+		Value    string
+		Required bool
+	}{
+		{"d", this.D, true},
+		{"p", this.P, true},
+		{"q", this.Q, true},
+		{"dp", this.DP, false},
+		{"dq", this.DQ, false},
+		{"qi", this.QI, false},
+	} {
+		value, err := decodeMember(member.Name, member.Value, member.Required)
+		if err != nil {
+			return nil, err
+		}
+		if value != nil {
+			members[member.Name] = new(big.Int).SetBytes(value)
+		}
+	}
+	privateKey := &rsa.PrivateKey{
+		PublicKey: publicKey,
+		D:         members["d"],
+		Primes:    []*big.Int{members["p"], members["q"]},
+	}
+	if err := privateKey.Validate(); err != nil {
+		return nil, invalidJWK("inconsistent RSA private key: %s", err.Error())
+	}
+	privateKey.Precompute()
+	for name, value := range map[string]*big.Int{
+		"dp": privateKey.Precomputed.Dp,
+		"dq": privateKey.Precomputed.Dq,
+		"qi": privateKey.Precomputed.Qinv,
+	} {
+		if members[name] != nil && members[name].Cmp(value) != 0 {
+			return nil, invalidJWK("inconsistent RSA private key member %q", name)
+		}
+	}
+	return privateKey, nil
+}
+
+func (this *jwkJSON) ecKey() (interface{}, error) {
+	if this.N != "" || this.E != "" || this.K != "" || this.P != "" || this.Q != "" {
+		return nil, invalidJWK("EC key with members of other key types")
+	}
+	curve, exists := curvesByName[this.Curve]
+	if !exists {
+		return nil, invalidJWK("unsupported EC crv %q", this.Curve)
+	}
+	size := (curve.Params().BitSize + 7) / 8
+	x, err := decodeFixedMember("x", this.X, size)
+	if err != nil {
+		return nil, err
+	}
+	y, err := decodeFixedMember("y", this.Y, size)
+	if err != nil {
+		return nil, err
+	}
+	publicKey := ecdsa.PublicKey{
+		Curve: curve,
+		X:     new(big.Int).SetBytes(x),
+		Y:     new(big.Int).SetBytes(y),
+	}
+	if !curve.IsOnCurve(publicKey.X, publicKey.Y) {
+		return nil, invalidJWK("EC point is not on curve %s", this.Curve)
+	}
+	if this.D == "" {
+		return &publicKey, nil
+	}
+	d, err := decodeFixedMember("d", this.D, size)
+	if err != nil {
+		return nil, err
+	}
+	privateKey := &ecdsa.PrivateKey{
+		PublicKey: publicKey,
+		D:         new(big.Int).SetBytes(d),
+	}
+	if privateKey.D.Sign() == 0 || privateKey.D.Cmp(curve.Params().N) >= 0 {
+		return nil, invalidJWK("invalid EC private key")
+	}
+	px, py := curve.ScalarBaseMult(d)
+	if px.Cmp(publicKey.X) != 0 || py.Cmp(publicKey.Y) != 0 {
+		return nil, invalidJWK("EC private key does not match public key")
+	}
+	return privateKey, nil
+}
+
+func (this *jwkJSON) okpKey() (interface{}, error) {
+	if this.N != "" || this.E != "" || this.Y != "" || this.K != "" || this.P != "" || this.Q != "" {
+		return nil, invalidJWK("OKP key with members of other key types")
+	}
+	switch this.Curve {
+	case CrvEd25519:
+		return this.ed25519Key()
+	case CrvX25519:
+		return this.x25519Key()
+	}
+	return nil, invalidJWK("unsupported OKP crv %q", this.Curve)
+}
+
+func (this *jwkJSON) ed25519Key() (interface{}, error) {
+	x, err := decodeFixedMember("x", this.X, ed25519.PublicKeySize)
+	if err != nil {
+		return nil, err
+	}
+	if this.D == "" {
+		return ed25519.PublicKey(x), nil
+	}
+	d, err := decodeFixedMember("d", this.D, ed25519.SeedSize)
+	if err != nil {
+		return nil, err
+	}
+	privateKey := ed25519.NewKeyFromSeed(d)
+	if subtle.ConstantTimeCompare(privateKey[ed25519.SeedSize:], x) != 1 {
+		return nil, invalidJWK("OKP private key does not match public key")
+	}
+	return privateKey, nil
+}
+
+func (this *jwkJSON) x25519Key() (interface{}, error) {
+	x, err := decodeFixedMember("x", this.X, X25519KeySize)
+	if err != nil {
+		return nil, err
+	}
+	if this.D == "" {
+		return X25519PublicKey(x), nil
+	}
+	d, err := decodeFixedMember("d", this.D, X25519KeySize)
+	if err != nil {
+		return nil, err
+	}
+	privateKey := X25519PrivateKey(d)
+	if !privateKey.Public().(X25519PublicKey).Equal(X25519PublicKey(x)) {
+		return nil, invalidJWK("OKP private key does not match public key")
+	}
+	return privateKey, nil
+}
+
+func (this *jwkJSON) octKey() (interface{}, error) {
+	if this.N != "" || this.E != "" || this.Curve != "" || this.X != "" || this.Y != "" || this.D != "" {
+		return nil, invalidJWK("oct key with members of other key types")
+	}
+	return decodeMember("k", this.K, true)
+}
+
+var (
+	curvesByName = map[string]elliptic.Curve{
+		CrvP256: elliptic.P256(),
+		CrvP384: elliptic.P384(),
+		CrvP521: elliptic.P521(),
+	}
+)
+
+// keyID returns the kid member of a JWK or the SHA-256 thumbprint of an asymmetric key,
+// which is used as the kid of a JWT. Secrets have no default kid, as it would publish a hash of the secret.
+func keyID(key interface{}) string {
+	if isNilKey(key) {
+		return ""
+	}
+	switch k := key.(type) {
+	case *BoundKey:
+		return keyID(k.Key)
+	case *JWK:
+		if k.KeyID != "" {
+			return k.KeyID
+		}
+		key = k.Key
+		if isNilKey(key) {
+			return ""
+		}
+	}
+	if kty, _ := keyType(key); kty == "" || kty == KtyOct {
+		return ""
+	}
+	thumbprint, err := (&JWK{Key: key}).Thumbprint(crypto.SHA256)
+	if err != nil {
+		return ""
+	}
+	return thumbprint
+}
+
+func keyType(key interface{}) (kty, crv string) {
+	switch k := key.(type) {
+	case *rsa.PublicKey, *rsa.PrivateKey:
+		return KtyRSA, ""
+	case *ecdsa.PublicKey:
+		return KtyEC, curveName(k.Curve)
+	case *ecdsa.PrivateKey:
+		return KtyEC, curveName(k.Curve)
+	case ed25519.PublicKey, ed25519.PrivateKey:
+		return KtyOKP, CrvEd25519
+	case X25519PublicKey, X25519PrivateKey:
+		return KtyOKP, CrvX25519
+	case []byte:
+		return KtyOct, ""
+	}
+	return "", ""
+}
+
+func curveName(curve elliptic.Curve) string {
+	if curve == nil {
+		return ""
+	}
+	if _, exists := curvesByName[curve.Params().Name]; !exists {
+		return ""
+	}
+	return curve.Params().Name
+}
+
+func encodeBigInt(value *big.Int, size int) string {
+	if size == 0 {
+		return base64.RawURLEncoding.EncodeToString(value.Bytes())
+	}
+	return base64.RawURLEncoding.EncodeToString(value.FillBytes(make([]byte, size)))
+}
+
+func decodeMember(name, value string, required bool) ([]byte, error) {
+	if value == "" {
+		if required {
+			return nil, invalidJWK("missing %s member", name)
+		}
+		return nil, nil
+	}
+	if strings.ContainsAny(value, "=+/") {
+		return nil, invalidJWK("%s member is not base64url encoded", name)
+	}
+	res, err := base64.RawURLEncoding.Strict().DecodeString(value)
+	if err != nil || len(res) == 0 {
+		return nil, invalidJWK("%s member is not base64url encoded", name)
+	}
+	return res, nil
+}
+
+func decodeFixedMember(name, value string, size int) ([]byte, error) {
+	res, err := decodeMember(name, value, true)
+	if err != nil {
+		return nil, err
+	}
+	if len(res) != size {
+		return nil, invalidJWK("%s member must have a length of %d bytes", name, size)
+	}
+	return res, nil
+}
+
+func invalidJWK(format string, args ...interface{}) error {
+	return fmt.Errorf("%w: %s", ErrInvJWK, fmt.Sprintf(format, args...))
+}
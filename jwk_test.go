@@ -0,0 +1,434 @@
+package gojwt_test
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/json"
+	"errors"
+	"github.com/tobyguelly/gojwt"
+	"strings"
+	"testing"
+)
+
+func TestJWK_Unmarshal(t *testing.T) {
+	tests := []struct {
+		Input           string
+		ExpectedKeyType string
+		ExpectedPrivate bool
+		ExpectedError   error
+	}{
+		{
+			Input:           `{"kty":"EC","crv":"P-256","x":"MKBCTNIcKUSDii11ySs3526iDZ8AiTo7Tu6KPAqv7D4","y":"4Etl6SRW2YiLUrN5vfvVHuhp7x8PxltmWWlbbM4IFyM","use":"enc","kid":"1"}`,
+			ExpectedKeyType: gojwt.KtyEC,
+			ExpectedPrivate: false,
+			ExpectedError:   nil,
+		},
+		{
+			Input:           `{"kty":"EC","crv":"P-256","x":"MKBCTNIcKUSDii11ySs3526iDZ8AiTo7Tu6KPAqv7D4","y":"4Etl6SRW2YiLUrN5vfvVHuhp7x8PxltmWWlbbM4IFyM","d":"870MB6gfuTJ4HtUnUvYMyJpr5eUZNP4Bk43bVdj3eAE","use":"enc","kid":"1"}`,
+			ExpectedKeyType: gojwt.KtyEC,
+			ExpectedPrivate: true,
+			ExpectedError:   nil,
+		},
+		{
+			Input:           `{"kty":"OKP","crv":"Ed25519","d":"nWGxne_9WmC6hEr0kuwsxERJxWl7MmkZcDusAxyuf2A","x":"11qYAYKxCrfVS_7TyWQHOg7hcvPapiMlrwIaaPcHURo"}`,
+			ExpectedKeyType: gojwt.KtyOKP,
+			ExpectedPrivate: true,
+			ExpectedError:   nil,
+		},
+		{
+			Input:           `{"kty":"OKP","crv":"X25519","d":"dwdtCnMYpX08FsFyUbJmRd9ML4frwJkqsXf7pR25LCo","x":"hSDwCYkwp1R0i33ctD73Wg2_Og0mOBr066SpjqqbTmo"}`,
+			ExpectedKeyType: gojwt.KtyOKP,
+			ExpectedPrivate: true,
+			ExpectedError:   nil,
+		},
+		{
+			Input:           `{"kty":"OKP","crv":"X25519","alg":"ECDH-ES","kid":"Bob","x":"3p7bfXt9wbTTW2HC7OQ1Nz-DQ8hbeGdNrfx-FG-IK08"}`,
+			ExpectedKeyType: gojwt.KtyOKP,
+			ExpectedPrivate: false,
+			ExpectedError:   nil,
+		},
+		{
+			Input:           `{"kty":"oct","alg":"A128KW","k":"GawgguFyGrWKav7AX4VKUg"}`,
+			ExpectedKeyType: gojwt.KtyOct,
+			ExpectedPrivate: true,
+			ExpectedError:   nil,
+		},
+		{
+			Input:         `{"kty":"EC","crv":"P-256","x":"MKBCTNIcKUSDii11ySs3526iDZ8AiTo7Tu6KPAqv7D5","y":"4Etl6SRW2YiLUrN5vfvVHuhp7x8PxltmWWlbbM4IFyM"}`,
+			ExpectedError: gojwt.ErrInvJWK,
+		},
+		{
+			Input:         `{"kty":"EC","crv":"P-256","x":"NKBCTNIcKUSDii11ySs3526iDZ8AiTo7Tu6KPAqv7D4","y":"4Etl6SRW2YiLUrN5vfvVHuhp7x8PxltmWWlbbM4IFyM"}`,
+			ExpectedError: gojwt.ErrInvJWK,
+		},
+		{
+			Input:         `{"kty":"EC","crv":"P-256","x":"MKBCTNIcKUSDii11ySs3526iDZ8AiTo7Tu6KPAqv","y":"4Etl6SRW2YiLUrN5vfvVHuhp7x8PxltmWWlbbM4IFyM"}`,
+			ExpectedError: gojwt.ErrInvJWK,
+		},
+		{
+			Input:         `{"kty":"EC","crv":"P-256K","x":"MKBCTNIcKUSDii11ySs3526iDZ8AiTo7Tu6KPAqv7D4","y":"4Etl6SRW2YiLUrN5vfvVHuhp7x8PxltmWWlbbM4IFyM"}`,
+			ExpectedError: gojwt.ErrInvJWK,
+		},
+		{
+			Input:         `{"kty":"EC","crv":"P-256","alg":"RS256","x":"MKBCTNIcKUSDii11ySs3526iDZ8AiTo7Tu6KPAqv7D4","y":"4Etl6SRW2YiLUrN5vfvVHuhp7x8PxltmWWlbbM4IFyM"}`,
+			ExpectedError: gojwt.ErrInvJWK,
+		},
+		{
+			Input:         `{"kty":"EC","crv":"P-256","alg":"ES384","x":"MKBCTNIcKUSDii11ySs3526iDZ8AiTo7Tu6KPAqv7D4","y":"4Etl6SRW2YiLUrN5vfvVHuhp7x8PxltmWWlbbM4IFyM"}`,
+			ExpectedError: gojwt.ErrInvJWK,
+		},
+		{
+			Input:         `{"kty":"OKP","crv":"Ed25519","d":"nWGxne_9WmC6hEr0kuwsxERJxWl7MmkZcDusAxyuf2A","x":"MKBCTNIcKUSDii11ySs3526iDZ8AiTo7Tu6KPAqv7D4"}`,
+			ExpectedError: gojwt.ErrInvJWK,
+		},
+		{
+			Input:         `{"kty":"OKP","crv":"X25519","d":"dwdtCnMYpX08FsFyUbJmRd9ML4frwJkqsXf7pR25LCo","x":"3p7bfXt9wbTTW2HC7OQ1Nz-DQ8hbeGdNrfx-FG-IK08"}`,
+			ExpectedError: gojwt.ErrInvJWK,
+		},
+		{
+			Input:         `{"kty":"oct","alg":"ECDH-ES","k":"GawgguFyGrWKav7AX4VKUg"}`,
+			ExpectedError: gojwt.ErrInvJWK,
+		},
+		{
+			Input:         `{"kty":"oct","k":"GawgguFyGrWKav7AX4VKUg==","use":"sig"}`,
+			ExpectedError: gojwt.ErrInvJWK,
+		},
+		{
+			Input:         `{"kty":"oct","k":"GawgguFyGrWKav7AX4VKUg","use":"sig","key_ops":["encrypt"]}`,
+			ExpectedError: gojwt.ErrInvJWK,
+		},
+		{
+			Input:         `{"kty":"oct","k":"GawgguFyGrWKav7AX4VKUg","key_ops":["sign","sign"]}`,
+			ExpectedError: gojwt.ErrInvJWK,
+		},
+		{
+			Input:         `{"kty":"oct"}`,
+			ExpectedError: gojwt.ErrInvJWK,
+		},
+		{
+			Input:         `{"kty":"RSA","n":"0vx7agoebGcQSuuPiLJXZptN9nndrQmbXEps2aiAFbWhM78LhWx4cbbfAAtVT86zwu1RK7aPFFxuhDR1L6tSoc_BJECPebWKRXjBZCiFV4n3oknjhMstn64tZ_2W-5JsGY4Hc5n9yBXArwl93lqt7_RN5w6Cf0h4QyQ5v-65YGjQR0_FDW2QvzqY368QQMicAtaSqzs8KJZgnYb9c7d0zgdAZHzu6qMQvRL5hajrn1n91CbOpbISD08qNLyrdkt-bFTWhAI4vMQFh6WeZu0fM4lFd2NcRwr3XPksINHaQ-G_xBniIqbw0Ls1jF44-csFCur-kEgU8awapJzKnqDKgw"}`,
+			ExpectedError: gojwt.ErrInvJWK,
+		},
+		{
+			Input:         `{"kty":"EC","crv":"P-256","k":"GawgguFyGrWKav7AX4VKUg","x":"MKBCTNIcKUSDii11ySs3526iDZ8AiTo7Tu6KPAqv7D4","y":"4Etl6SRW2YiLUrN5vfvVHuhp7x8PxltmWWlbbM4IFyM"}`,
+			ExpectedError: gojwt.ErrInvJWK,
+		},
+		{
+			Input:         `{"kty":"XYZ"}`,
+			ExpectedError: gojwt.ErrInvJWK,
+		},
+		{
+			Input:         `{"crv":"P-256"}`,
+			ExpectedError: gojwt.ErrInvJWK,
+		},
+	}
+	for i, test := range tests {
+		jwk, err := gojwt.LoadJWK([]byte(test.Input))
+		if !errors.Is(err, test.ExpectedError) {
+			t.Errorf("Output and expected output did not match: %s\nFound:\t\t%v\nExpected:\t%v",
+				test.Input, err, test.ExpectedError,
+			)
+			continue
+		}
+		if err == nil && (jwk.KeyType() != test.ExpectedKeyType || jwk.IsPrivate() != test.ExpectedPrivate) {
+			t.Errorf("Output and expected output did not match: %s\nFound:\t\t%s %t\nExpected:\t%s %t",
+				test.Input, jwk.KeyType(), jwk.IsPrivate(), test.ExpectedKeyType, test.ExpectedPrivate,
+			)
+			continue
+		}
+		t.Logf("Passed %d/%d tests!", i+1, len(tests))
+	}
+}
+
+func TestJWK_Marshal(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Errorf("Failed test because of error: %s", err.Error())
+		t.FailNow()
+	}
+	ecKey, err := ecdsa.GenerateKey(elliptic.P521(), rand.Reader)
+	if err != nil {
+		t.Errorf("Failed test because of error: %s", err.Error())
+		t.FailNow()
+	}
+	_, edKey, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Errorf("Failed test because of error: %s", err.Error())
+		t.FailNow()
+	}
+	x25519Key, err := gojwt.GenerateX25519Key()
+	if err != nil {
+		t.Errorf("Failed test because of error: %s", err.Error())
+		t.FailNow()
+	}
+	tests := []struct {
+		Input          interface{}
+		ExpectedPublic bool
+		ExpectedError  error
+	}{
+		{
+			Input:          rsaKey,
+			ExpectedPublic: true,
+			ExpectedError:  nil,
+		},
+		{
+			Input:          &rsaKey.PublicKey,
+			ExpectedPublic: true,
+			ExpectedError:  nil,
+		},
+		{
+			Input:          ecKey,
+			ExpectedPublic: true,
+			ExpectedError:  nil,
+		},
+		{
+			Input:          edKey,
+			ExpectedPublic: true,
+			ExpectedError:  nil,
+		},
+		{
+			Input:          x25519Key,
+			ExpectedPublic: true,
+			ExpectedError:  nil,
+		},
+		{
+			Input:          "secret",
+			ExpectedPublic: false,
+			ExpectedError:  nil,
+		},
+		{
+			Input:         42,
+			ExpectedError: gojwt.ErrInvKeyTyp,
+		},
+	}
+	for i, test := range tests {
+		jwk, err := gojwt.NewJWK(test.Input)
+		if !errors.Is(err, test.ExpectedError) {
+			t.Errorf("Output and expected output did not match: %T\nFound:\t\t%v\nExpected:\t%v",
+				test.Input, err, test.ExpectedError,
+			)
+			continue
+		}
+		if err != nil {
+			t.Logf("Passed %d/%d tests!", i+1, len(tests))
+			continue
+		}
+		jwk.KeyID = "key"
+		jwk.KeyOperations = []string{"sign", "verify"}
+		data, err := json.Marshal(jwk)
+		if err != nil {
+			t.Errorf("Failed test because of error: %s", err.Error())
+			continue
+		}
+		loaded, err := gojwt.LoadJWK(data)
+		if err != nil {
+			t.Errorf("Failed test because of error: %s", err.Error())
+			continue
+		}
+		res, err := json.Marshal(loaded)
+		if err != nil || string(res) != string(data) {
+			t.Errorf("Output and expected output did not match: %T\nFound:\t\t%s\nExpected:\t%s",
+				test.Input, res, data,
+			)
+			continue
+		}
+		public, err := jwk.Public()
+		if (err == nil) != test.ExpectedPublic {
+			t.Errorf("Output and expected output did not match: %T\nFound:\t\t%v\nExpected:\t%t",
+				test.Input, err, test.ExpectedPublic,
+			)
+			continue
+		}
+		if public != nil {
+			res, err = json.Marshal(public)
+			if err != nil || strings.Contains(string(res), `"d"`) || strings.Contains(string(res), `"sign"`) || public.IsPrivate() {
+				t.Errorf("Output and expected output did not match: %T\nFound:\t\t%s\nExpected:\t%s",
+					test.Input, res, "no private members",
+				)
+				continue
+			}
+		}
+		t.Logf("Passed %d/%d tests!", i+1, len(tests))
+	}
+}
+
+func TestJWK_Public(t *testing.T) {
+	privateKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Errorf("Failed test because of error: %s", err.Error())
+		t.FailNow()
+	}
+	tests := []struct {
+		Input         []string
+		Expected      []string
+		ExpectedError error
+	}{
+		{
+			Input:    nil,
+			Expected: nil,
+		},
+		{
+			Input:    []string{"sign", "verify"},
+			Expected: []string{"verify"},
+		},
+		{
+			Input:    []string{"decrypt", "unwrapKey"},
+			Expected: []string{"encrypt", "wrapKey"},
+		},
+		{
+			Input:    []string{"deriveKey"},
+			Expected: []string{"deriveKey"},
+		},
+		{
+			Input:         []string{"custom"},
+			ExpectedError: gojwt.ErrInvKeyUse,
+		},
+	}
+	for i, test := range tests {
+		jwk := &gojwt.JWK{Key: privateKey, KeyOperations: test.Input}
+		public, err := jwk.Public()
+		var operations []string
+		if err == nil {
+			operations = public.KeyOperations
+		}
+		if errors.Is(err, test.ExpectedError) && strings.Join(operations, " ") == strings.Join(test.Expected, " ") {
+			t.Logf("Passed %d/%d tests!", i+1, len(tests))
+		} else {
+			t.Errorf("Output and expected output did not match: %v\nFound:\t\t%v %v\nExpected:\t%v %v",
+				test.Input, operations, err, test.Expected, test.ExpectedError,
+			)
+		}
+	}
+	jwt := gojwt.NewJWT()
+	jwt.Header.Algorithm = gojwt.AlgES256
+	err = jwt.SignWithKey(privateKey)
+	if err != nil {
+		t.Errorf("Failed test because of error: %s", err.Error())
+		t.FailNow()
+	}
+	public, err := (&gojwt.JWK{Key: privateKey, KeyOperations: []string{"deriveKey"}}).Public()
+	if err != nil {
+		t.Errorf("Failed test because of error: %s", err.Error())
+		t.FailNow()
+	}
+	err = jwt.ValidateWithKey(public)
+	if !errors.Is(err, gojwt.ErrInvKeyUse) {
+		t.Errorf("Output and expected output did not match: %s\nFound:\t\t%v\nExpected:\t%v", "deriveKey", err, gojwt.ErrInvKeyUse)
+	}
+}
+
+func TestJWK_SignAndValidate(t *testing.T) {
+	privateKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Errorf("Failed test because of error: %s", err.Error())
+		t.FailNow()
+	}
+	tests := []struct {
+		SigningKey    *gojwt.JWK
+		VerifyingKey  *gojwt.JWK
+		ExpectedError error
+	}{
+		{
+			SigningKey:    &gojwt.JWK{Key: privateKey},
+			VerifyingKey:  &gojwt.JWK{Key: &privateKey.PublicKey},
+			ExpectedError: nil,
+		},
+		{
+			SigningKey:    &gojwt.JWK{Key: privateKey, Algorithm: gojwt.AlgES256, Use: gojwt.UseSig},
+			VerifyingKey:  &gojwt.JWK{Key: &privateKey.PublicKey, Algorithm: gojwt.AlgES256, KeyOperations: []string{"verify"}},
+			ExpectedError: nil,
+		},
+		{
+			SigningKey:    &gojwt.JWK{Key: privateKey, Algorithm: gojwt.AlgES384},
+			VerifyingKey:  &gojwt.JWK{Key: &privateKey.PublicKey},
+			ExpectedError: gojwt.ErrAlgNotAll,
+		},
+		{
+			SigningKey:    &gojwt.JWK{Key: privateKey},
+			VerifyingKey:  &gojwt.JWK{Key: &privateKey.PublicKey, Algorithm: gojwt.AlgES384},
+			ExpectedError: gojwt.ErrAlgNotAll,
+		},
+		{
+			SigningKey:    &gojwt.JWK{Key: privateKey, Use: gojwt.UseEnc},
+			VerifyingKey:  &gojwt.JWK{Key: &privateKey.PublicKey},
+			ExpectedError: gojwt.ErrInvKeyUse,
+		},
+		{
+			SigningKey:    &gojwt.JWK{Key: privateKey},
+			VerifyingKey:  &gojwt.JWK{Key: &privateKey.PublicKey, KeyOperations: []string{"sign"}},
+			ExpectedError: gojwt.ErrInvKeyUse,
+		},
+	}
+	for i, test := range tests {
+		jwt := gojwt.NewJWT()
+		jwt.Header.Algorithm = gojwt.AlgES256
+		err := jwt.SignWithKey(test.SigningKey)
+		if err == nil {
+			err = jwt.ValidateWithKey(test.VerifyingKey)
+		}
+		if errors.Is(err, test.ExpectedError) {
+			t.Logf("Passed %d/%d tests!", i+1, len(tests))
+		} else {
+			t.Errorf("Output and expected output did not match: %d\nFound:\t\t%v\nExpected:\t%v",
+				i, err, test.ExpectedError,
+			)
+		}
+	}
+}
+
+func TestJWK_Thumbprint(t *testing.T) {
+	privateKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Errorf("Failed test because of error: %s", err.Error())
+		t.FailNow()
+	}
+	publicThumbprint, err := (&gojwt.JWK{Key: &privateKey.PublicKey}).Thumbprint(crypto.SHA256)
+	if err != nil {
+		t.Errorf("Failed test because of error: %s", err.Error())
+		t.FailNow()
+	}
+	tests := []struct {
+		Input          string
+		Key            interface{}
+		Hash           crypto.Hash
+		ExpectedOutput string
+	}{
+		{
+			Input:          `{"kty":"RSA","n":"0vx7agoebGcQSuuPiLJXZptN9nndrQmbXEps2aiAFbWhM78LhWx4cbbfAAtVT86zwu1RK7aPFFxuhDR1L6tSoc_BJECPebWKRXjBZCiFV4n3oknjhMstn64tZ_2W-5JsGY4Hc5n9yBXArwl93lqt7_RN5w6Cf0h4QyQ5v-65YGjQR0_FDW2QvzqY368QQMicAtaSqzs8KJZgnYb9c7d0zgdAZHzu6qMQvRL5hajrn1n91CbOpbISD08qNLyrdkt-bFTWhAI4vMQFh6WeZu0fM4lFd2NcRwr3XPksINHaQ-G_xBniIqbw0Ls1jF44-csFCur-kEgU8awapJzKnqDKgw","e":"AQAB","alg":"RS256","kid":"2011-04-29"}`,
+			Hash:           crypto.SHA256,
+			ExpectedOutput: "NzbLsXh8uDCcd-6MNwXF4W_7noWXFZAfHkxZsRGC9Xs",
+		},
+		{
+			Input:          `{"kty":"OKP","crv":"Ed25519","x":"11qYAYKxCrfVS_7TyWQHOg7hcvPapiMlrwIaaPcHURo"}`,
+			Hash:           crypto.SHA256,
+			ExpectedOutput: "kPrK_qmxVWaYVA9wwBF6Iuo3vVzz7TxHCTwXBygrS4k",
+		},
+		{
+			Key:            privateKey,
+			Hash:           crypto.SHA256,
+			ExpectedOutput: publicThumbprint,
+		},
+	}
+	for i, test := range tests {
+		jwk := &gojwt.JWK{Key: test.Key}
+		if test.Input != "" {
+			jwk, err = gojwt.LoadJWK([]byte(test.Input))
+			if err != nil {
+				t.Errorf("Failed test because of error: %s", err.Error())
+				continue
+			}
+		}
+		res, err := jwk.Thumbprint(test.Hash)
+		if err == nil && res == test.ExpectedOutput {
+			t.Logf("Passed %d/%d tests!", i+1, len(tests))
+		} else {
+			t.Errorf("Output and expected output did not match: %s\nFound:\t\t%s %v\nExpected:\t%s",
+				test.Input, res, err, test.ExpectedOutput,
+			)
+		}
+	}
+}
@@ -0,0 +1,182 @@
+package gojwt
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// KeyProvider provides the keys a JWT may be verified with, based on its Header.
+// It is implemented by JWKSet and can be passed to all methods validating a JWT with a key.
+type KeyProvider interface {
+
+	// KeysFor returns the keys for the kid and the algorithm in the Header.
+	// Returns ErrKeyNotFnd if no key matches.
+	KeysFor(header Header) ([]*JWK, error)
+}
+
+// JWKSet is a JSON Web Key Set as specified in RFC 7517, holding several keys, like the current
+// and the previous keys of an issuer, which rotates its keys.
+type JWKSet struct {
+
+	// Keys holds the keys of the set.
+	Keys []*JWK `json:"keys"`
+}
+
+// LoadJWKSet creates a JWKSet from its JSON representation.
+// Keys with an unsupported kty or crv member are ignored, as recommended by RFC 7517.
+// Returns an error wrapping ErrInvJWK if any other key is malformed or inconsistent.
+func LoadJWKSet(data []byte) (*JWKSet, error) {
+	set := &JWKSet{}
+	err := json.Unmarshal(data, set)
+	if err != nil {
+		return nil, err
+	}
+	return set, nil
+}
+
+// Key returns the first key with a kid member or nil, if the set does not contain the key.
+func (this *JWKSet) Key(kid string) *JWK {
+	for _, key := range this.Keys {
+		if key.KeyID == kid {
+			return key
+		}
+	}
+	return nil
+}
+
+// KeysFor is the implementation of the KeyProvider interface.
+// If the Header has a kid, the keys with the same kid are returned, otherwise all keys, which can be
+// used to verify the algorithm in the Header. Keys without a kid match their RFC 7638 thumbprint (SHA-256).
+// Returns ErrKeyNotFnd if no key matches.
+func (this *JWKSet) KeysFor(header Header) ([]*JWK, error) {
+	var keys []*JWK
+	for _, key := range this.Keys {
+		if header.KeyID != "" && !key.identifiedBy(header.KeyID) {
+			continue
+		}
+		if key.compatible(header.Algorithm) && key.Allows(header.Algorithm, "verify") {
+			keys = append(keys, key)
+		}
+	}
+	if len(keys) == 0 {
+		return nil, ErrKeyNotFnd
+	}
+	return keys, nil
+}
+
+// Public returns a copy of the JWKSet holding the public keys only, without any private members.
+// Symmetric keys are removed from the set.
+func (this *JWKSet) Public() *JWKSet {
+	set := &JWKSet{Keys: []*JWK{}}
+	for _, key := range this.Keys {
+		public, err := key.Public()
+		if err == nil {
+			set.Keys = append(set.Keys, public)
+		}
+	}
+	return set
+}
+
+// UnmarshalJSON is the implementation of the json.Unmarshaler interface.
+func (this *JWKSet) UnmarshalJSON(data []byte) error {
+	var raw struct {
+		Keys []json.RawMessage `json:"keys"`
+	}
+	err := json.Unmarshal(data, &raw)
+	if err != nil {
+		return fmt.Errorf("%w: %s", ErrInvJWK, err.Error())
+	}
+	if raw.Keys == nil {
+		return invalidJWK("missing keys member")
+	}
+	keys := make([]*JWK, 0, len(raw.Keys))
+	for _, data := range raw.Keys {
+		var member struct {
+			KeyType string `json:"kty"`
+			Curve   string `json:"crv"`
+		}
+		err = json.Unmarshal(data, &member)
+		if err != nil {
+			return fmt.Errorf("%w: %s", ErrInvJWK, err.Error())
+		}
+		if !supportedKey(member.KeyType, member.Curve) {
+			continue
+		}
+		key := &JWK{}
+		err = json.Unmarshal(data, key)
+		if err != nil {
+			return err
+		}
+		keys = append(keys, key)
+	}
+	this.Keys = keys
+	return nil
+}
+
+func (this *JWK) identifiedBy(kid string) bool {
+	if this.KeyID != "" {
+		return this.KeyID == kid
+	}
+	return keyID(this) == kid
+}
+
+func (this *JWK) compatible(alg string) bool {
+	kty, crv := keyType(this.Key)
+	if expected, exists := algorithmKeyTypes[alg]; exists && !contains(expected, kty) {
+		return false
+	}
+	if expected, exists := algorithmCurves[alg]; exists && expected != crv {
+		return false
+	}
+	return true
+}
+
+func supportedKey(kty, crv string) bool {
+	switch kty {
+	case KtyRSA, KtyOct:
+		return true
+	case KtyEC:
+		_, exists := curvesByName[crv]
+		return exists
+	case KtyOKP:
+		return crv == CrvEd25519 || crv == CrvX25519
+	}
+	return false
+}
+
+func verifyWithKeys(registry *Registry, jwt *JWT, provider KeyProvider) error {
+	_, err := verifyWithProvider(registry, jwt.Header, provider, jwt.VerifySignature)
+	return err
+}
+
+// verifyWithProvider tries the keys of a KeyProvider matching a header until one of them verifies the signature
+// and returns the key, which has verified the signature.
+func verifyWithProvider(registry *Registry, header Header, provider KeyProvider, verify func(Verifier) error) (*JWK, error) {
+	if isNilKey(provider) {
+		return nil, ErrInvKeyTyp
+	}
+	keys, err := provider.KeysFor(header)
+	if err != nil {
+		return nil, err
+	}
+	for _, key := range keys {
+		var verifier Verifier
+		verifier, err = registry.Verifier(header.Algorithm, key)
+		if err == nil {
+			err = verify(verifier)
+		}
+		if err == nil {
+			return key, nil
+		}
+		if !retryWithKey(err) {
+			return nil, err
+		}
+	}
+	return nil, err
+}
+
+func retryWithKey(err error) bool {
+	return errors.Is(err, ErrInvSecKey) || errors.Is(err, ErrInvKeyTyp) || errors.Is(err, ErrInvKeyCrv) ||
+		errors.Is(err, ErrAlgNotAll) || errors.Is(err, ErrInvKeyUse)
+}
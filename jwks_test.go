@@ -0,0 +1,218 @@
+package gojwt_test
+
+import (
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"errors"
+	"github.com/tobyguelly/gojwt"
+	"testing"
+)
+
+func TestJWKSet_Unmarshal(t *testing.T) {
+	tests := []struct {
+		Input          string
+		ExpectedOutput int
+		ExpectedError  error
+	}{
+		{
+			Input:          `{"keys":[{"kty":"EC","crv":"P-256","x":"MKBCTNIcKUSDii11ySs3526iDZ8AiTo7Tu6KPAqv7D4","y":"4Etl6SRW2YiLUrN5vfvVHuhp7x8PxltmWWlbbM4IFyM","kid":"1"},{"kty":"oct","k":"GawgguFyGrWKav7AX4VKUg","kid":"2"}]}`,
+			ExpectedOutput: 2,
+			ExpectedError:  nil,
+		},
+		{
+			Input:          `{"keys":[{"kty":"OKP","crv":"X448","x":"AAAA"},{"kty":"PQC","pub":"AAAA"},{"kty":"oct","k":"GawgguFyGrWKav7AX4VKUg"}]}`,
+			ExpectedOutput: 1,
+			ExpectedError:  nil,
+		},
+		{
+			Input:          `{"keys":[]}`,
+			ExpectedOutput: 0,
+			ExpectedError:  nil,
+		},
+		{
+			Input:         `{"keys":[{"kty":"EC","crv":"P-256","x":"NKBCTNIcKUSDii11ySs3526iDZ8AiTo7Tu6KPAqv7D4","y":"4Etl6SRW2YiLUrN5vfvVHuhp7x8PxltmWWlbbM4IFyM"}]}`,
+			ExpectedError: gojwt.ErrInvJWK,
+		},
+		{
+			Input:         `{"kty":"oct","k":"GawgguFyGrWKav7AX4VKUg"}`,
+			ExpectedError: gojwt.ErrInvJWK,
+		},
+		{
+			Input:         `{"keys":{}}`,
+			ExpectedError: gojwt.ErrInvJWK,
+		},
+	}
+	for i, test := range tests {
+		set, err := gojwt.LoadJWKSet([]byte(test.Input))
+		if !errors.Is(err, test.ExpectedError) {
+			t.Errorf("Output and expected output did not match: %s\nFound:\t\t%v\nExpected:\t%v",
+				test.Input, err, test.ExpectedError,
+			)
+			continue
+		}
+		if err == nil && len(set.Keys) != test.ExpectedOutput {
+			t.Errorf("Output and expected output did not match: %s\nFound:\t\t%d\nExpected:\t%d",
+				test.Input, len(set.Keys), test.ExpectedOutput,
+			)
+			continue
+		}
+		t.Logf("Passed %d/%d tests!", i+1, len(tests))
+	}
+}
+
+func TestJWT_ValidateWithKeySet(t *testing.T) {
+	currentKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Errorf("Failed test because of error: %s", err.Error())
+		t.FailNow()
+	}
+	previousKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Errorf("Failed test because of error: %s", err.Error())
+		t.FailNow()
+	}
+	otherCurveKey, err := ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+	if err != nil {
+		t.Errorf("Failed test because of error: %s", err.Error())
+		t.FailNow()
+	}
+	unnamedKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Errorf("Failed test because of error: %s", err.Error())
+		t.FailNow()
+	}
+	edPublicKey, edPrivateKey, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Errorf("Failed test because of error: %s", err.Error())
+		t.FailNow()
+	}
+	set := &gojwt.JWKSet{
+		Keys: []*gojwt.JWK{
+			{Key: &currentKey.PublicKey, KeyID: "current"},
+			{Key: &previousKey.PublicKey, KeyID: "previous"},
+			{Key: edPublicKey, KeyID: "ed"},
+			{Key: []byte("secret"), KeyID: "hmac"},
+			{Key: &unnamedKey.PublicKey},
+		},
+	}
+	tests := []struct {
+		Algorithm     string
+		KeyID         string
+		Key           interface{}
+		AutoKeyID     bool
+		Validator     *gojwt.Validator
+		ExpectedError error
+	}{
+		{
+			Algorithm:     gojwt.AlgES256,
+			KeyID:         "current",
+			Key:           currentKey,
+			ExpectedError: nil,
+		},
+		{
+			Algorithm:     gojwt.AlgES256,
+			KeyID:         "previous",
+			Key:           previousKey,
+			ExpectedError: nil,
+		},
+		{
+			Algorithm:     gojwt.AlgES256,
+			KeyID:         "",
+			Key:           previousKey,
+			ExpectedError: nil,
+		},
+		{
+			Algorithm:     gojwt.AlgEdDSA,
+			KeyID:         "",
+			Key:           edPrivateKey,
+			ExpectedError: nil,
+		},
+		{
+			Algorithm:     gojwt.AlgHS256,
+			KeyID:         "hmac",
+			Key:           "secret",
+			ExpectedError: nil,
+		},
+		{
+			Algorithm:     gojwt.AlgES256,
+			Key:           unnamedKey,
+			AutoKeyID:     true,
+			ExpectedError: nil,
+		},
+		{
+			Algorithm:     gojwt.AlgES256,
+			Key:           currentKey,
+			AutoKeyID:     true,
+			ExpectedError: gojwt.ErrKeyNotFnd,
+		},
+		{
+			Algorithm:     gojwt.AlgES256,
+			KeyID:         "current",
+			Key:           previousKey,
+			ExpectedError: gojwt.ErrInvSecKey,
+		},
+		{
+			Algorithm:     gojwt.AlgES256,
+			KeyID:         "unknown",
+			Key:           currentKey,
+			ExpectedError: gojwt.ErrKeyNotFnd,
+		},
+		{
+			Algorithm:     gojwt.AlgES256,
+			KeyID:         "ed",
+			Key:           currentKey,
+			ExpectedError: gojwt.ErrKeyNotFnd,
+		},
+		{
+			Algorithm:     gojwt.AlgES384,
+			KeyID:         "",
+			Key:           otherCurveKey,
+			ExpectedError: gojwt.ErrKeyNotFnd,
+		},
+		{
+			Algorithm:     gojwt.AlgES256,
+			KeyID:         "current",
+			Key:           currentKey,
+			Validator:     gojwt.NewValidator(gojwt.AllowAlgorithms(gojwt.AlgES256)),
+			ExpectedError: nil,
+		},
+		{
+			Algorithm:     gojwt.AlgHS256,
+			KeyID:         "hmac",
+			Key:           "secret",
+			Validator:     gojwt.NewValidator(gojwt.AllowAlgorithms(gojwt.AlgES256)),
+			ExpectedError: gojwt.ErrAlgNotAll,
+		},
+	}
+	for i, test := range tests {
+		jwt := gojwt.NewJWT()
+		jwt.Header.Algorithm = test.Algorithm
+		jwt.Header.KeyID = test.KeyID
+		var err error
+		if test.AutoKeyID {
+			err = jwt.SignWithKey(test.Key)
+		} else {
+			var signer gojwt.Signer
+			signer, err = gojwt.DefaultRegistry.Signer(test.Algorithm, test.Key)
+			if err == nil {
+				err = jwt.SignWith(signer)
+			}
+		}
+		if err == nil {
+			if test.Validator != nil {
+				err = test.Validator.Validate(&jwt, set)
+			} else {
+				err = jwt.ValidateWithKeySet(set)
+			}
+		}
+		if errors.Is(err, test.ExpectedError) {
+			t.Logf("Passed %d/%d tests!", i+1, len(tests))
+		} else {
+			t.Errorf("Output and expected output did not match: %s\nFound:\t\t%v\nExpected:\t%v",
+				test.KeyID, err, test.ExpectedError,
+			)
+		}
+	}
+}
@@ -0,0 +1,588 @@
+package gojwt
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"strings"
+)
+
+// JWSHeader is a header of a JWS as specified in RFC 7515. In the JSON serialization, every signature has
+// a protected header, which is integrity protected by the signature, and an unprotected header.
+type JWSHeader struct {
+
+	// Algorithm is the identification of the algorithm used for signing the JWS.
+	Algorithm string `json:"alg,omitempty"`
+
+	// ContentType indicates the content type of the payload, not required.
+	ContentType string `json:"cty,omitempty"`
+
+	// Type indicates the type of the token, not required.
+	Type string `json:"typ,omitempty"`
+
+	// KeyID identifies the key used for signing the JWS, not required.
+	KeyID string `json:"kid,omitempty"`
+
+	// Base64 is the b64 member specified in RFC 7797, which is false if the payload is signed unencoded, not required.
+	// It must be in the protected header and is added to the Critical members when signing.
+	Base64 *bool `json:"b64,omitempty"`
+
+	// Critical holds the names of the header members, which must be understood for verifying the JWS, not required.
+	// It must be in the protected header and only "b64" is supported.
+	Critical []string `json:"crit,omitempty"`
+}
+
+var (
+	supportedCriticalHeaders = map[string]bool{
+		"b64": true,
+	}
+)
+
+// IsEmpty returns a bool, whether the JWSHeader is empty or not.
+func (this *JWSHeader) IsEmpty() bool {
+	return this.Algorithm == "" && this.ContentType == "" && this.Type == "" && this.KeyID == "" &&
+		this.Base64 == nil && this.Critical == nil
+}
+
+// IsEncoded returns a bool, whether the payload is base64 rawURLEncoded, which is the case unless b64 is false.
+func (this *JWSHeader) IsEncoded() bool {
+	return this.Base64 == nil || *this.Base64
+}
+
+// JWSSignature is a signature of a JWS with its protected and unprotected header.
+type JWSSignature struct {
+
+	// Protected is the protected header, which is integrity protected by the signature.
+	Protected JWSHeader
+
+	// Header is the unprotected header, which is not integrity protected, not required.
+	Header JWSHeader
+
+	// Signature is the base64 rawURLEncoded signature of the protected header and the payload.
+	Signature string
+
+	// rawProtected holds the encoded protected header, which is signed.
+	rawProtected string
+
+	// verifiedBy holds the key of a KeyProvider, which has verified the signature during Verify.
+	verifiedBy *JWK
+}
+
+// JOSEHeader returns the union of the protected and the unprotected header, which describes the signature.
+// Returns ErrBadJWSTok if a member is present in both headers, as they must be disjoint,
+// or if the unprotected header contains the b64 or crit member.
+func (this *JWSSignature) JOSEHeader() (JWSHeader, error) {
+	if this.Header.Base64 != nil || this.Header.Critical != nil {
+		return JWSHeader{}, ErrBadJWSTok
+	}
+	res := this.Protected
+	members := []struct {
+		protected   *string
+		unprotected string
+	}{
+		{&res.Algorithm, this.Header.Algorithm},
+		{&res.ContentType, this.Header.ContentType},
+		{&res.Type, this.Header.Type},
+		{&res.KeyID, this.Header.KeyID},
+	}
+	for _, member := range members {
+		if member.unprotected == "" {
+			continue
+		}
+		if *member.protected != "" {
+			return JWSHeader{}, ErrBadJWSTok
+		}
+		*member.protected = member.unprotected
+	}
+	return res, nil
+}
+
+// SignatureRequirement decides, whether the valid signatures of a JWS are sufficient for the JWS to be valid.
+// The valid slice holds for every signature, whether it has been verified successfully.
+type SignatureRequirement func(signatures []JWSSignature, valid []bool) bool
+
+// RequireAllSignatures requires all signatures of a JWS to be valid.
+func RequireAllSignatures() SignatureRequirement {
+	return func(signatures []JWSSignature, valid []bool) bool {
+		for _, ok := range valid {
+			if !ok {
+				return false
+			}
+		}
+		return true
+	}
+}
+
+// RequireAnySignature requires at least one signature of a JWS to be valid.
+func RequireAnySignature() SignatureRequirement {
+	return func(signatures []JWSSignature, valid []bool) bool {
+		for _, ok := range valid {
+			if ok {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// RequireSignaturesByKeyID requires a valid signature for every kid (KeyID), other signatures are ignored.
+// Only the kid of the protected header is considered, because the unprotected header is not signed.
+// As the kid is chosen by the signer, the JWS must be verified with a KeyProvider, like a JWKSet,
+// and the signature must be verified by the key of the KeyProvider with the kid. Signatures verified
+// with a single key never match. Without kids, no signatures are sufficient.
+func RequireSignaturesByKeyID(kids ...string) SignatureRequirement {
+	return func(signatures []JWSSignature, valid []bool) bool {
+		if len(kids) == 0 {
+			return false
+		}
+		for _, kid := range kids {
+			found := false
+			for i, signature := range signatures {
+				if valid[i] && signature.Protected.KeyID == kid && signature.verifiedBy != nil &&
+					signature.verifiedBy.identifiedBy(kid) {
+					found = true
+					break
+				}
+			}
+			if !found {
+				return false
+			}
+		}
+		return true
+	}
+}
+
+// JWS is a payload signed by one or more signatures, serialized in the JWS compact serialization
+// or the general or flattened JWS JSON serialization specified in RFC 7515.
+// In contrast to JWT, the payload is not required to hold claims.
+type JWS struct {
+
+	// Payload is the signed payload.
+	Payload []byte
+
+	// Signatures holds the signatures of the payload.
+	Signatures []JWSSignature
+
+	// Detached is true if the payload is not included in the serialization of the JWS, as specified
+	// in RFC 7515 appendix F. The payload of a detached JWS is supplied for verifying it, like with LoadJWSDetached.
+	Detached bool
+}
+
+type jwsJSON struct {
+	Payload    *string            `json:"payload,omitempty"`
+	Signatures []jwsSignatureJSON `json:"signatures,omitempty"`
+	jwsSignatureJSON
+}
+
+type jwsSignatureJSON struct {
+	Protected string     `json:"protected,omitempty"`
+	Header    *JWSHeader `json:"header,omitempty"`
+	Signature string     `json:"signature,omitempty"`
+}
+
+// NewJWS creates a new JWS object with a payload and without any signatures.
+func NewJWS(payload []byte) JWS {
+	return JWS{
+		Payload: payload,
+	}
+}
+
+// LoadJWS creates a JWS object from a JWS string in the compact serialization
+// or in the general or flattened JSON serialization.
+// The original encoded protected headers are kept, as they are signed.
+// A JWS without a payload is loaded as Detached, its payload must be supplied before verifying it.
+// Returns ErrBadJWSTok if the string is not a valid JWS, ErrInvCrit if a crit (Critical) member is not supported
+// or returns the JWS if everything was successful.
+func LoadJWS(token string) (*JWS, error) {
+	res := &JWS{}
+	if strings.HasPrefix(strings.TrimSpace(token), "{") {
+		err := res.UnmarshalJSON([]byte(token))
+		if err != nil {
+			return nil, err
+		}
+		return res, nil
+	}
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 || parts[0] == "" {
+		return nil, ErrBadJWSTok
+	}
+	signature, err := loadJWSSignature(parts[0], nil, parts[2])
+	if err != nil {
+		return nil, err
+	}
+	res.Signatures = []JWSSignature{signature}
+	err = res.loadPayload(&parts[1])
+	if err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// LoadJWSDetached creates a JWS object from a JWS string with a detached payload like LoadJWS and attaches
+// the payload, which is transmitted separately, like the body of a webhook request.
+// Returns ErrBadJWSTok if the string is not a valid JWS or contains a payload and the same errors as LoadJWS otherwise.
+func LoadJWSDetached(token string, payload []byte) (*JWS, error) {
+	res, err := LoadJWS(token)
+	if err != nil {
+		return nil, err
+	}
+	if !res.Detached {
+		return nil, ErrBadJWSTok
+	}
+	res.Payload = payload
+	return res, nil
+}
+
+// IsSigned returns a bool, whether the JWS has at least one signature.
+func (this *JWS) IsSigned() bool {
+	return len(this.Signatures) != 0
+}
+
+// SignWithKey adds a signature to the JWS using a given key and the Signer of the DefaultRegistry for the algorithm
+// in the protected header. If both headers have no KeyID, it is set in the protected header to the kid of a JWK
+// or the RFC 7638 thumbprint (SHA-256) of an asymmetric key.
+// Returns ErrAlgNotImp if the algorithm is not implemented yet
+// or returns ErrInvKeyTyp if the key can not be used with the algorithm.
+func (this *JWS) SignWithKey(protected, unprotected JWSHeader, key interface{}) error {
+	signer, err := DefaultRegistry.Signer(protected.Algorithm, key)
+	if err != nil {
+		return err
+	}
+	if protected.KeyID == "" && unprotected.KeyID == "" {
+		protected.KeyID = keyID(key)
+	}
+	return this.SignWith(signer, protected, unprotected)
+}
+
+// SignWith adds a signature to the JWS using a Signer with a protected and an unprotected header.
+// The algorithm in the protected header is set to the algorithm of the Signer and removed from the unprotected header.
+// If b64 (Base64) is false in the protected header, the payload is signed unencoded as specified in RFC 7797
+// and "b64" is added to the crit (Critical) members.
+// Returns ErrBadJWSTok if the headers are not disjoint or the signatures of the JWS would disagree on b64
+// and ErrInvCrit if a crit member is not supported.
+func (this *JWS) SignWith(signer Signer, protected, unprotected JWSHeader) error {
+	protected.Algorithm = signer.Algorithm()
+	unprotected.Algorithm = ""
+	if !protected.IsEncoded() && !contains(protected.Critical, "b64") {
+		protected.Critical = append(append([]string(nil), protected.Critical...), "b64")
+	}
+	err := protected.validateCritical()
+	if err != nil {
+		return err
+	}
+	if this.IsSigned() && this.isEncoded() != protected.IsEncoded() {
+		return ErrBadJWSTok
+	}
+	data, err := json.Marshal(protected)
+	if err != nil {
+		return err
+	}
+	signature := JWSSignature{
+		Protected:    protected,
+		Header:       unprotected,
+		rawProtected: base64.RawURLEncoding.EncodeToString(data),
+	}
+	_, err = signature.JOSEHeader()
+	if err != nil {
+		return err
+	}
+	signature.Signature, err = signer.Sign(this.signingInput(&signature))
+	if err != nil {
+		return err
+	}
+	this.Signatures = append(this.Signatures, signature)
+	return nil
+}
+
+// VerifySignature verifies the signature at an index with a key, using the Verifier of the DefaultRegistry
+// for the algorithm in the header of the signature. If the key is a KeyProvider, like a JWKSet, the keys are
+// selected by the kid (KeyID) in the header of the signature.
+// Returns ErrBadJWSTok if there is no signature at the index or its headers are not disjoint,
+// ErrKeyNotFnd if a KeyProvider has no matching key and the same errors as the ValidateWithKey method of JWT otherwise.
+func (this *JWS) VerifySignature(index int, key interface{}) error {
+	_, err := this.verifySignature(index, key)
+	return err
+}
+
+// verifySignature verifies the signature at an index like VerifySignature and returns the key of a KeyProvider,
+// which has verified the signature.
+func (this *JWS) verifySignature(index int, key interface{}) (*JWK, error) {
+	if index < 0 || index >= len(this.Signatures) {
+		return nil, ErrBadJWSTok
+	}
+	signature := &this.Signatures[index]
+	header, err := signature.JOSEHeader()
+	if err != nil {
+		return nil, err
+	}
+	verify := func(verifier Verifier) error {
+		if verifier.Algorithm() != header.Algorithm {
+			return ErrAlgNotAll
+		}
+		err := verifier.Verify(this.signingInput(signature), signature.Signature)
+		if errors.Is(err, ErrInvSecKey) {
+			return &ValidationError{Err: ErrInvSecKey, Value: header.Algorithm}
+		}
+		return err
+	}
+	if provider, ok := key.(KeyProvider); ok {
+		return verifyWithProvider(DefaultRegistry, Header{
+			Algorithm:   header.Algorithm,
+			ContentType: header.ContentType,
+			Type:        header.Type,
+			KeyID:       header.KeyID,
+		}, provider, verify)
+	}
+	verifier, err := DefaultRegistry.Verifier(header.Algorithm, key)
+	if err != nil {
+		return nil, err
+	}
+	return nil, verify(verifier)
+}
+
+// Verify verifies all signatures of the JWS with a key like VerifySignature
+// and checks the valid signatures with a SignatureRequirement.
+// Returns ErrTokNotSig if the JWS has no signatures and ErrInvSigs if the valid signatures do not meet the requirement.
+// The failures of the single signatures can be checked with VerifySignature.
+func (this *JWS) Verify(key interface{}, requirement SignatureRequirement) error {
+	if !this.IsSigned() {
+		return ErrTokNotSig
+	}
+	signatures := make([]JWSSignature, len(this.Signatures))
+	valid := make([]bool, len(this.Signatures))
+	for i := range this.Signatures {
+		signatures[i] = this.Signatures[i]
+		jwk, err := this.verifySignature(i, key)
+		signatures[i].verifiedBy, valid[i] = jwk, err == nil
+	}
+	if !requirement(signatures, valid) {
+		return ErrInvSigs
+	}
+	return nil
+}
+
+// Parse formats the JWS into a JWS string in the compact serialization and returns the result.
+// It requires the JWS to have exactly one signature without an unprotected header,
+// otherwise it returns ErrTokNotSig or ErrBadJWSTok. An unencoded payload must not contain a ".",
+// unless the JWS is Detached, whose payload is left empty.
+// Result = Base64Encode(Protected) + "." + Base64Encode(Payload) + "." + Signature
+func (this *JWS) Parse() (string, error) {
+	if !this.IsSigned() {
+		return "", ErrTokNotSig
+	}
+	signature := &this.Signatures[0]
+	if len(this.Signatures) != 1 || !signature.Header.IsEmpty() || signature.rawProtected == "" {
+		return "", ErrBadJWSTok
+	}
+	payload := ""
+	if !this.Detached {
+		payload = this.encodedPayload(this.isEncoded())
+		if strings.Contains(payload, ".") {
+			return "", ErrBadJWSTok
+		}
+	}
+	return signature.rawProtected + "." + payload + "." + signature.Signature, nil
+}
+
+// ParseJSON formats the JWS into a JWS string in the general JSON serialization and returns the result.
+// It requires the JWS to be signed, otherwise it returns ErrTokNotSig.
+func (this *JWS) ParseJSON() (string, error) {
+	if !this.IsSigned() {
+		return "", ErrTokNotSig
+	}
+	res, err := json.Marshal(this)
+	return string(res), err
+}
+
+// ParseFlattened formats the JWS into a JWS string in the flattened JSON serialization and returns the result.
+// It requires the JWS to have exactly one signature, otherwise it returns ErrTokNotSig or ErrBadJWSTok.
+func (this *JWS) ParseFlattened() (string, error) {
+	if !this.IsSigned() {
+		return "", ErrTokNotSig
+	}
+	if len(this.Signatures) != 1 {
+		return "", ErrBadJWSTok
+	}
+	res, err := json.Marshal(jwsJSON{
+		Payload:          this.serializedPayload(),
+		jwsSignatureJSON: this.Signatures[0].toJSON(),
+	})
+	return string(res), err
+}
+
+// String formats the JWS into a JWS string and ignores probable errors.
+// JWS with exactly one signature and no unprotected header are formatted in the compact serialization,
+// all others in the general JSON serialization.
+func (this *JWS) String() string {
+	if res, err := this.Parse(); err == nil {
+		return res
+	}
+	res, _ := this.ParseJSON()
+	return res
+}
+
+// MarshalJSON is the implementation of the json.Marshaler interface,
+// which formats the JWS in the general JSON serialization.
+func (this JWS) MarshalJSON() ([]byte, error) {
+	res := jwsJSON{
+		Payload:    this.serializedPayload(),
+		Signatures: make([]jwsSignatureJSON, len(this.Signatures)),
+	}
+	for i := range this.Signatures {
+		res.Signatures[i] = this.Signatures[i].toJSON()
+	}
+	return json.Marshal(res)
+}
+
+// UnmarshalJSON is the implementation of the json.Unmarshaler interface,
+// which loads a JWS in the general or flattened JSON serialization.
+// Returns ErrBadJWSTok if the JWS is malformed or mixes the members of the general and the flattened serialization,
+// as specified in RFC 7515 section 7.2.2, and ErrInvCrit if a crit (Critical) member is not supported.
+func (this *JWS) UnmarshalJSON(data []byte) error {
+	var raw jwsJSON
+	err := json.Unmarshal(data, &raw)
+	if err != nil {
+		return ErrBadJWSTok
+	}
+	var members map[string]json.RawMessage
+	err = json.Unmarshal(data, &members)
+	if err != nil {
+		return ErrBadJWSTok
+	}
+	_, general := members["signatures"]
+	flattened := false
+	for _, member := range []string{"protected", "header", "signature"} {
+		if _, ok := members[member]; ok {
+			flattened = true
+		}
+	}
+	if flattened == general || general && len(raw.Signatures) == 0 {
+		return ErrBadJWSTok
+	}
+	if flattened {
+		raw.Signatures = []jwsSignatureJSON{raw.jwsSignatureJSON}
+	}
+	res := JWS{
+		Signatures: make([]JWSSignature, len(raw.Signatures)),
+	}
+	for i, signature := range raw.Signatures {
+		res.Signatures[i], err = loadJWSSignature(signature.Protected, signature.Header, signature.Signature)
+		if err != nil {
+			return err
+		}
+	}
+	err = res.loadPayload(raw.Payload)
+	if err != nil {
+		return err
+	}
+	*this = res
+	return nil
+}
+
+// loadPayload sets the payload of a loaded JWS from its serialization, which is Detached if it is empty.
+func (this *JWS) loadPayload(payload *string) error {
+	for _, signature := range this.Signatures {
+		if signature.Protected.IsEncoded() != this.isEncoded() {
+			return ErrBadJWSTok
+		}
+	}
+	if payload == nil || *payload == "" {
+		this.Detached = true
+		return nil
+	}
+	if !this.isEncoded() {
+		this.Payload = []byte(*payload)
+		return nil
+	}
+	var err error
+	this.Payload, err = base64.RawURLEncoding.Strict().DecodeString(*payload)
+	if err != nil {
+		return ErrBadJWSTok
+	}
+	return nil
+}
+
+// isEncoded returns a bool, whether the payload is base64 rawURLEncoded, which all signatures of a JWS must agree on.
+func (this *JWS) isEncoded() bool {
+	return !this.IsSigned() || this.Signatures[0].Protected.IsEncoded()
+}
+
+func (this *JWS) encodedPayload(encoded bool) string {
+	if !encoded {
+		return string(this.Payload)
+	}
+	return base64.RawURLEncoding.EncodeToString(this.Payload)
+}
+
+func (this *JWS) serializedPayload() *string {
+	if this.Detached {
+		return nil
+	}
+	payload := this.encodedPayload(this.isEncoded())
+	return &payload
+}
+
+func (this *JWS) signingInput(signature *JWSSignature) string {
+	return signature.rawProtected + "." + this.encodedPayload(signature.Protected.IsEncoded())
+}
+
+// validateCritical checks, whether the crit (Critical) members are supported and present in the header
+// and whether an unencoded payload is declared as critical, as required by RFC 7797.
+func (this *JWSHeader) validateCritical() error {
+	if this.Critical != nil && len(this.Critical) == 0 {
+		return ErrInvCrit
+	}
+	for _, name := range this.Critical {
+		if !supportedCriticalHeaders[name] {
+			return ErrInvCrit
+		}
+	}
+	if !this.IsEncoded() && !contains(this.Critical, "b64") || contains(this.Critical, "b64") && this.Base64 == nil {
+		return ErrInvCrit
+	}
+	return nil
+}
+
+func (this *JWSSignature) toJSON() jwsSignatureJSON {
+	res := jwsSignatureJSON{
+		Protected: this.rawProtected,
+		Signature: this.Signature,
+	}
+	if !this.Header.IsEmpty() {
+		header := this.Header
+		res.Header = &header
+	}
+	return res
+}
+
+func loadJWSSignature(protected string, unprotected *JWSHeader, signature string) (JWSSignature, error) {
+	res := JWSSignature{
+		Signature:    signature,
+		rawProtected: protected,
+	}
+	if signature == "" {
+		return JWSSignature{}, ErrBadJWSTok
+	}
+	if unprotected != nil {
+		res.Header = *unprotected
+	}
+	if protected != "" {
+		data, err := base64.RawURLEncoding.DecodeString(protected)
+		if err != nil {
+			return JWSSignature{}, ErrBadJWSTok
+		}
+		err = json.Unmarshal(data, &res.Protected)
+		if err != nil {
+			return JWSSignature{}, ErrBadJWSTok
+		}
+	}
+	header, err := res.JOSEHeader()
+	if err != nil || header.Algorithm == "" {
+		return JWSSignature{}, ErrBadJWSTok
+	}
+	err = res.Protected.validateCritical()
+	if err != nil {
+		return JWSSignature{}, err
+	}
+	return res, nil
+}
@@ -0,0 +1,524 @@
+package gojwt_test
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"errors"
+	"github.com/tobyguelly/gojwt"
+	"strings"
+	"testing"
+)
+
+type allKeysProvider []*gojwt.JWK
+
+func (this allKeysProvider) KeysFor(header gojwt.Header) ([]*gojwt.JWK, error) {
+	return this, nil
+}
+
+func TestLoadJWS(t *testing.T) {
+	jws := gojwt.NewJWS([]byte(`{"iss":"joe","exp":1300819380}`))
+	err := jws.SignWithKey(gojwt.JWSHeader{Algorithm: gojwt.AlgHS256}, gojwt.JWSHeader{}, "secret")
+	if err != nil {
+		t.Errorf("Failed test because of error: %s", err.Error())
+		t.FailNow()
+	}
+	compact, err := jws.Parse()
+	if err != nil {
+		t.Errorf("Failed test because of error: %s", err.Error())
+		t.FailNow()
+	}
+	flattened, err := jws.ParseFlattened()
+	if err != nil {
+		t.Errorf("Failed test because of error: %s", err.Error())
+		t.FailNow()
+	}
+	err = jws.SignWithKey(gojwt.JWSHeader{Algorithm: gojwt.AlgHS512}, gojwt.JWSHeader{KeyID: "other"}, "other")
+	if err != nil {
+		t.Errorf("Failed test because of error: %s", err.Error())
+		t.FailNow()
+	}
+	general, err := jws.ParseJSON()
+	if err != nil {
+		t.Errorf("Failed test because of error: %s", err.Error())
+		t.FailNow()
+	}
+	protected, signature := compact[:strings.Index(compact, ".")], compact[strings.LastIndex(compact, ".")+1:]
+	tests := []struct {
+		Input              string
+		ExpectedSignatures int
+		ExpectedError      error
+	}{
+		{
+			Input:              compact,
+			ExpectedSignatures: 1,
+			ExpectedError:      nil,
+		},
+		{
+			Input:              flattened,
+			ExpectedSignatures: 1,
+			ExpectedError:      nil,
+		},
+		{
+			Input:              general,
+			ExpectedSignatures: 2,
+			ExpectedError:      nil,
+		},
+		{
+			Input:              `{"payload":"e30","header":{"alg":"HS256"},"signature":"` + signature + `"}`,
+			ExpectedSignatures: 1,
+			ExpectedError:      nil,
+		},
+		{
+			Input:         `{"payload":"e30","protected":"` + protected + `","header":{"alg":"HS256"},"signature":"` + signature + `"}`,
+			ExpectedError: gojwt.ErrBadJWSTok,
+		},
+		{
+			Input:         `{"payload":"e30","protected":"` + protected + `","signature":"` + signature + `","signatures":[]}`,
+			ExpectedError: gojwt.ErrBadJWSTok,
+		},
+		{
+			Input:         `{"payload":"e30","protected":"` + protected + `","signature":"` + signature + `","signatures":[{"protected":"` + protected + `","signature":"` + signature + `"}]}`,
+			ExpectedError: gojwt.ErrBadJWSTok,
+		},
+		{
+			Input:         `{"payload":"e30","protected":"` + protected + `","signature":"` + signature + `","signatures":null}`,
+			ExpectedError: gojwt.ErrBadJWSTok,
+		},
+		{
+			Input:         `{"payload":"e30","header":null,"signatures":[{"protected":"` + protected + `","signature":"` + signature + `"}]}`,
+			ExpectedError: gojwt.ErrBadJWSTok,
+		},
+		{
+			Input:         `{"payload":"e30"}`,
+			ExpectedError: gojwt.ErrBadJWSTok,
+		},
+		{
+			Input:              `{"protected":"` + protected + `","signature":"` + signature + `"}`,
+			ExpectedSignatures: 1,
+			ExpectedError:      nil,
+		},
+		{
+			Input:         `{"payload":"e30","signature":"` + signature + `"}`,
+			ExpectedError: gojwt.ErrBadJWSTok,
+		},
+		{
+			Input:         `{"payload":"e30","signatures":[{"protected":"` + protected + `"}]}`,
+			ExpectedError: gojwt.ErrBadJWSTok,
+		},
+		{
+			Input:         `{"payload":"e30"`,
+			ExpectedError: gojwt.ErrBadJWSTok,
+		},
+		{
+			Input:         compact[:strings.LastIndex(compact, ".")],
+			ExpectedError: gojwt.ErrBadJWSTok,
+		},
+	}
+	for i, test := range tests {
+		res, err := gojwt.LoadJWS(test.Input)
+		if errors.Is(err, test.ExpectedError) && (err != nil || len(res.Signatures) == test.ExpectedSignatures) {
+			t.Logf("Passed %d/%d tests!", i+1, len(tests))
+		} else {
+			t.Errorf("Output and expected output did not match: %s\nFound:\t\t%v\nExpected:\t%d %v",
+				test.Input, err, test.ExpectedSignatures, test.ExpectedError,
+			)
+		}
+	}
+}
+
+func TestJWS_Parse(t *testing.T) {
+	unsigned := gojwt.NewJWS([]byte("payload"))
+	single := gojwt.NewJWS([]byte("payload"))
+	err := single.SignWithKey(gojwt.JWSHeader{Algorithm: gojwt.AlgHS256}, gojwt.JWSHeader{}, "secret")
+	if err != nil {
+		t.Errorf("Failed test because of error: %s", err.Error())
+		t.FailNow()
+	}
+	unprotected := gojwt.NewJWS([]byte("payload"))
+	err = unprotected.SignWithKey(gojwt.JWSHeader{Algorithm: gojwt.AlgHS256}, gojwt.JWSHeader{KeyID: "key"}, "secret")
+	if err != nil {
+		t.Errorf("Failed test because of error: %s", err.Error())
+		t.FailNow()
+	}
+	multiple := gojwt.NewJWS([]byte("payload"))
+	for _, secret := range []string{"secret", "other"} {
+		err = multiple.SignWithKey(gojwt.JWSHeader{Algorithm: gojwt.AlgHS256}, gojwt.JWSHeader{}, secret)
+		if err != nil {
+			t.Errorf("Failed test because of error: %s", err.Error())
+			t.FailNow()
+		}
+	}
+	tests := []struct {
+		Input                  gojwt.JWS
+		ExpectedCompactError   error
+		ExpectedFlattenedError error
+		ExpectedJSONError      error
+	}{
+		{
+			Input:                  single,
+			ExpectedCompactError:   nil,
+			ExpectedFlattenedError: nil,
+			ExpectedJSONError:      nil,
+		},
+		{
+			Input:                  unprotected,
+			ExpectedCompactError:   gojwt.ErrBadJWSTok,
+			ExpectedFlattenedError: nil,
+			ExpectedJSONError:      nil,
+		},
+		{
+			Input:                  multiple,
+			ExpectedCompactError:   gojwt.ErrBadJWSTok,
+			ExpectedFlattenedError: gojwt.ErrBadJWSTok,
+			ExpectedJSONError:      nil,
+		},
+		{
+			Input:                  unsigned,
+			ExpectedCompactError:   gojwt.ErrTokNotSig,
+			ExpectedFlattenedError: gojwt.ErrTokNotSig,
+			ExpectedJSONError:      gojwt.ErrTokNotSig,
+		},
+	}
+	for i, test := range tests {
+		_, compactErr := test.Input.Parse()
+		_, flattenedErr := test.Input.ParseFlattened()
+		token, jsonErr := test.Input.ParseJSON()
+		if jsonErr == nil {
+			var loaded *gojwt.JWS
+			loaded, jsonErr = gojwt.LoadJWS(token)
+			if jsonErr == nil {
+				jsonErr = loaded.Verify("secret", gojwt.RequireAnySignature())
+			}
+		}
+		if errors.Is(compactErr, test.ExpectedCompactError) && errors.Is(flattenedErr, test.ExpectedFlattenedError) &&
+			errors.Is(jsonErr, test.ExpectedJSONError) {
+			t.Logf("Passed %d/%d tests!", i+1, len(tests))
+		} else {
+			t.Errorf("Output and expected output did not match: %s\nFound:\t\t%v %v %v\nExpected:\t%v %v %v",
+				test.Input.String(), compactErr, flattenedErr, jsonErr,
+				test.ExpectedCompactError, test.ExpectedFlattenedError, test.ExpectedJSONError,
+			)
+		}
+	}
+}
+
+func TestJWS_Verify(t *testing.T) {
+	serviceKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Errorf("Failed test because of error: %s", err.Error())
+		t.FailNow()
+	}
+	orgKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Errorf("Failed test because of error: %s", err.Error())
+		t.FailNow()
+	}
+	jws := gojwt.NewJWS([]byte(`{"amount":100}`))
+	err = jws.SignWithKey(gojwt.JWSHeader{Algorithm: gojwt.AlgES256, KeyID: "service"}, gojwt.JWSHeader{}, serviceKey)
+	if err != nil {
+		t.Errorf("Failed test because of error: %s", err.Error())
+		t.FailNow()
+	}
+	err = jws.SignWithKey(gojwt.JWSHeader{Algorithm: gojwt.AlgPS256, KeyID: "org"}, gojwt.JWSHeader{}, orgKey)
+	if err != nil {
+		t.Errorf("Failed test because of error: %s", err.Error())
+		t.FailNow()
+	}
+	unprotected := gojwt.NewJWS([]byte(`{"amount":100}`))
+	err = unprotected.SignWithKey(gojwt.JWSHeader{Algorithm: gojwt.AlgPS256}, gojwt.JWSHeader{KeyID: "org"}, orgKey)
+	if err != nil {
+		t.Errorf("Failed test because of error: %s", err.Error())
+		t.FailNow()
+	}
+	unprotectedToken, err := unprotected.ParseJSON()
+	if err != nil {
+		t.Errorf("Failed test because of error: %s", err.Error())
+		t.FailNow()
+	}
+	token, err := jws.ParseJSON()
+	if err != nil {
+		t.Errorf("Failed test because of error: %s", err.Error())
+		t.FailNow()
+	}
+	mislabeled := gojwt.NewJWS([]byte(`{"amount":100}`))
+	err = mislabeled.SignWithKey(gojwt.JWSHeader{Algorithm: gojwt.AlgPS256, KeyID: "service"}, gojwt.JWSHeader{}, orgKey)
+	if err != nil {
+		t.Errorf("Failed test because of error: %s", err.Error())
+		t.FailNow()
+	}
+	mislabeledToken, err := mislabeled.ParseJSON()
+	if err != nil {
+		t.Errorf("Failed test because of error: %s", err.Error())
+		t.FailNow()
+	}
+	tampered := gojwt.NewJWS([]byte(`{"amount":999}`))
+	tampered.Signatures = jws.Signatures
+	tamperedToken, err := tampered.ParseJSON()
+	if err != nil {
+		t.Errorf("Failed test because of error: %s", err.Error())
+		t.FailNow()
+	}
+	keys := &gojwt.JWKSet{Keys: []*gojwt.JWK{
+		{Key: &serviceKey.PublicKey, KeyID: "service"},
+		{Key: &orgKey.PublicKey, KeyID: "org"},
+	}}
+	orgKeys := &gojwt.JWKSet{Keys: []*gojwt.JWK{
+		{Key: &orgKey.PublicKey, KeyID: "org"},
+	}}
+	tests := []struct {
+		Input         string
+		Key           interface{}
+		Requirement   gojwt.SignatureRequirement
+		ExpectedError error
+	}{
+		{
+			Input:         token,
+			Key:           keys,
+			Requirement:   gojwt.RequireAllSignatures(),
+			ExpectedError: nil,
+		},
+		{
+			Input:         token,
+			Key:           orgKeys,
+			Requirement:   gojwt.RequireAllSignatures(),
+			ExpectedError: gojwt.ErrInvSigs,
+		},
+		{
+			Input:         token,
+			Key:           orgKeys,
+			Requirement:   gojwt.RequireAnySignature(),
+			ExpectedError: nil,
+		},
+		{
+			Input:         token,
+			Key:           &orgKey.PublicKey,
+			Requirement:   gojwt.RequireAnySignature(),
+			ExpectedError: nil,
+		},
+		{
+			Input:         token,
+			Key:           orgKeys,
+			Requirement:   gojwt.RequireSignaturesByKeyID("org"),
+			ExpectedError: nil,
+		},
+		{
+			Input:         token,
+			Key:           orgKeys,
+			Requirement:   gojwt.RequireSignaturesByKeyID("service", "org"),
+			ExpectedError: gojwt.ErrInvSigs,
+		},
+		{
+			Input:         token,
+			Key:           keys,
+			Requirement:   gojwt.RequireSignaturesByKeyID("service", "hsm"),
+			ExpectedError: gojwt.ErrInvSigs,
+		},
+		{
+			Input:         token,
+			Key:           keys,
+			Requirement:   gojwt.RequireSignaturesByKeyID(),
+			ExpectedError: gojwt.ErrInvSigs,
+		},
+		{
+			Input:         token,
+			Key:           &orgKey.PublicKey,
+			Requirement:   gojwt.RequireSignaturesByKeyID("org"),
+			ExpectedError: gojwt.ErrInvSigs,
+		},
+		{
+			Input:         token,
+			Key:           allKeysProvider(keys.Keys),
+			Requirement:   gojwt.RequireSignaturesByKeyID("service", "org"),
+			ExpectedError: nil,
+		},
+		{
+			Input:         mislabeledToken,
+			Key:           allKeysProvider(orgKeys.Keys),
+			Requirement:   gojwt.RequireAnySignature(),
+			ExpectedError: nil,
+		},
+		{
+			Input:         mislabeledToken,
+			Key:           allKeysProvider(orgKeys.Keys),
+			Requirement:   gojwt.RequireSignaturesByKeyID("service"),
+			ExpectedError: gojwt.ErrInvSigs,
+		},
+		{
+			Input:         unprotectedToken,
+			Key:           orgKeys,
+			Requirement:   gojwt.RequireAnySignature(),
+			ExpectedError: nil,
+		},
+		{
+			Input:         unprotectedToken,
+			Key:           orgKeys,
+			Requirement:   gojwt.RequireSignaturesByKeyID("org"),
+			ExpectedError: gojwt.ErrInvSigs,
+		},
+		{
+			Input:         tamperedToken,
+			Key:           keys,
+			Requirement:   gojwt.RequireAnySignature(),
+			ExpectedError: gojwt.ErrInvSigs,
+		},
+	}
+	for i, test := range tests {
+		loaded, err := gojwt.LoadJWS(test.Input)
+		if err == nil {
+			err = loaded.Verify(test.Key, test.Requirement)
+		}
+		if errors.Is(err, test.ExpectedError) {
+			t.Logf("Passed %d/%d tests!", i+1, len(tests))
+		} else {
+			t.Errorf("Output and expected output did not match: %s\nFound:\t\t%v\nExpected:\t%v",
+				test.Input, err, test.ExpectedError,
+			)
+		}
+	}
+}
+
+func TestLoadJWSDetached(t *testing.T) {
+	// The key of RFC 7515 appendix A.1 and the examples of RFC 7797 section 4.
+	key, err := gojwt.LoadJWK([]byte(`{"kty":"oct","k":"AyM1SysPpbyDfgZld3umj1qzKObwVMkoqQ-EstJQLr_T-1qS0gZH75aKtMN3Yj0iPS4hcgUuTwjAzZr1Z9CAow"}`))
+	if err != nil {
+		t.Errorf("Failed test because of error: %s", err.Error())
+		t.FailNow()
+	}
+	encoded := "eyJhbGciOiJIUzI1NiJ9..5mvfOroL-g7HyqJoozehmsaqmvTYGEq5jTI1gVvoEoQ"
+	unencoded := "eyJhbGciOiJIUzI1NiIsImI2NCI6ZmFsc2UsImNyaXQiOlsiYjY0Il19..A5dxf2s96_n5FLueVuW1Z_vh161FwXZC4YLPff6dmDY"
+	attached := gojwt.NewJWS([]byte("$.02"))
+	err = attached.SignWithKey(gojwt.JWSHeader{Algorithm: gojwt.AlgHS256}, gojwt.JWSHeader{}, "secret")
+	if err != nil {
+		t.Errorf("Failed test because of error: %s", err.Error())
+		t.FailNow()
+	}
+	header := func(json string) string {
+		return base64.RawURLEncoding.EncodeToString([]byte(json))
+	}
+	tests := []struct {
+		Input         string
+		Payload       string
+		ExpectedError error
+	}{
+		{
+			Input:         encoded,
+			Payload:       "$.02",
+			ExpectedError: nil,
+		},
+		{
+			Input:         unencoded,
+			Payload:       "$.02",
+			ExpectedError: nil,
+		},
+		{
+			Input:         `{"protected":"eyJhbGciOiJIUzI1NiIsImI2NCI6ZmFsc2UsImNyaXQiOlsiYjY0Il19","signature":"A5dxf2s96_n5FLueVuW1Z_vh161FwXZC4YLPff6dmDY"}`,
+			Payload:       "$.02",
+			ExpectedError: nil,
+		},
+		{
+			Input:         unencoded,
+			Payload:       "$.03",
+			ExpectedError: gojwt.ErrInvSigs,
+		},
+		{
+			Input:         encoded,
+			Payload:       "JC4wMg",
+			ExpectedError: gojwt.ErrInvSigs,
+		},
+		{
+			Input:         attached.String(),
+			Payload:       "$.02",
+			ExpectedError: gojwt.ErrBadJWSTok,
+		},
+		{
+			Input:         header(`{"alg":"HS256","b64":false}`) + "..A5dxf2s96_n5FLueVuW1Z_vh161FwXZC4YLPff6dmDY",
+			Payload:       "$.02",
+			ExpectedError: gojwt.ErrInvCrit,
+		},
+		{
+			Input:         header(`{"alg":"HS256","crit":["exp"],"exp":1363284000}`) + "..A5dxf2s96_n5FLueVuW1Z_vh161FwXZC4YLPff6dmDY",
+			Payload:       "$.02",
+			ExpectedError: gojwt.ErrInvCrit,
+		},
+		{
+			Input:         header(`{"alg":"HS256","crit":["b64"]}`) + "..A5dxf2s96_n5FLueVuW1Z_vh161FwXZC4YLPff6dmDY",
+			Payload:       "$.02",
+			ExpectedError: gojwt.ErrInvCrit,
+		},
+		{
+			Input:         `{"protected":"eyJhbGciOiJIUzI1NiJ9","header":{"b64":false,"crit":["b64"]},"signature":"A5dxf2s96_n5FLueVuW1Z_vh161FwXZC4YLPff6dmDY"}`,
+			Payload:       "$.02",
+			ExpectedError: gojwt.ErrBadJWSTok,
+		},
+	}
+	for i, test := range tests {
+		jws, err := gojwt.LoadJWSDetached(test.Input, []byte(test.Payload))
+		if err == nil {
+			err = jws.Verify(key, gojwt.RequireAllSignatures())
+		}
+		if errors.Is(err, test.ExpectedError) {
+			t.Logf("Passed %d/%d tests!", i+1, len(tests))
+		} else {
+			t.Errorf("Output and expected output did not match: %s\nFound:\t\t%v\nExpected:\t%v",
+				test.Input, err, test.ExpectedError,
+			)
+		}
+	}
+}
+
+func TestJWS_SignUnencoded(t *testing.T) {
+	unencoded := false
+	tests := []struct {
+		Payload        string
+		Detached       bool
+		ExpectedOutput string
+		ExpectedError  error
+	}{
+		{
+			Payload:        "$02",
+			Detached:       false,
+			ExpectedOutput: "eyJhbGciOiJIUzI1NiIsImI2NCI6ZmFsc2UsImNyaXQiOlsiYjY0Il19.$02.",
+			ExpectedError:  nil,
+		},
+		{
+			Payload:        "$.02",
+			Detached:       true,
+			ExpectedOutput: "eyJhbGciOiJIUzI1NiIsImI2NCI6ZmFsc2UsImNyaXQiOlsiYjY0Il19..",
+			ExpectedError:  nil,
+		},
+		{
+			Payload:       "$.02",
+			Detached:      false,
+			ExpectedError: gojwt.ErrBadJWSTok,
+		},
+	}
+	for i, test := range tests {
+		jws := gojwt.NewJWS([]byte(test.Payload))
+		jws.Detached = test.Detached
+		err := jws.SignWithKey(gojwt.JWSHeader{Algorithm: gojwt.AlgHS256, Base64: &unencoded}, gojwt.JWSHeader{}, "secret")
+		if err != nil {
+			t.Errorf("Failed test because of error: %s", err.Error())
+			continue
+		}
+		res, err := jws.Parse()
+		var loaded *gojwt.JWS
+		if err == nil && test.Detached {
+			loaded, err = gojwt.LoadJWSDetached(res, []byte(test.Payload))
+		} else if err == nil {
+			loaded, err = gojwt.LoadJWS(res)
+		}
+		if err == nil {
+			err = loaded.Verify("secret", gojwt.RequireAllSignatures())
+		}
+		if errors.Is(err, test.ExpectedError) && strings.HasPrefix(res, test.ExpectedOutput) {
+			t.Logf("Passed %d/%d tests!", i+1, len(tests))
+		} else {
+			t.Errorf("Output and expected output did not match: %s\nFound:\t\t%s %v\nExpected:\t%s %v",
+				test.Payload, res, err, test.ExpectedOutput, test.ExpectedError,
+			)
+		}
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"errors"
 	"strings"
+	"time"
 )
 
 // JWT is a struct holding the values a JWT.
@@ -19,6 +20,15 @@ type JWT struct {
 	// Signature is a string holding the JWT Header and the JWT Payload encrypted with the algorithm of the JWT Header.
 	// Signature = Header.Algorithm(Header.Json() + "." + Payload.Json(), SECRET)
 	Signature string
+
+	// rawHeader and rawPayload hold the original encoded segments of a loaded JWT,
+	// which are signed instead of the formatted Header and Payload until the JWT is signed again.
+	rawHeader  string
+	rawPayload string
+
+	// autoKeyID holds the KeyID, which has been set by SignWithKey or loaded from a token,
+	// so it is replaced when the JWT is signed with another key.
+	autoKeyID string
 }
 
 // NewJWT creates a completely new JWT object with default values.
@@ -32,6 +42,8 @@ func NewJWT() JWT {
 }
 
 // LoadJWT creates a JWT object from a JWT string.
+// The original encoded header and payload are kept, so the signature is validated against them,
+// regardless of how the Header and Payload are formatted by this package.
 // Returns empty JWT, ErrBadJWTTok if the JWT is not a valid JWT,
 // or returns the JWT if everything was successful.
 func LoadJWT(token string) (jwt *JWT, err error) {
@@ -67,6 +79,9 @@ func LoadJWT(token string) (jwt *JWT, err error) {
 	payload.applyCustom(payloadMap)
 	res.Payload = payload
 	res.Signature = jwtParts[2]
+	res.rawHeader = jwtParts[0]
+	res.rawPayload = jwtParts[1]
+	res.autoKeyID = header.KeyID
 	return res, nil
 }
 
@@ -82,51 +97,113 @@ func (this *JWT) IsSigned() (signed bool) {
 
 // IsExpired returns a bool, whether the token has already expired or is not valid yet.
 func (this *JWT) IsExpired() (expired bool) {
-	return (this.Payload.ExpirationTime != nil && this.Payload.ExpirationTime.Unix() <= Now().Unix()) ||
-		(this.Payload.NotBefore != nil && this.Payload.NotBefore.Unix() >= Now().Unix())
+	now := time.Now()
+	return hasExpired(this.Payload.ExpirationTime, now, 0) || isNotActive(this.Payload.NotBefore, now, 0)
 }
 
 // Validate validates a JWT based on a given secret string using a symmetric encryption algorithm.
 // Returns ErrAlgNotImp if the algorithm in the Header is not implemented yet,
-// ErrTokNotSig if the token has not been signed yet, ErrInvTokPrd if the token period has expired
-// and ErrInvSecKey if the entered secret string is invalid corresponding to the signature.
-// Returns nil if the JWT is validated with the entered secret.
+// ErrTokNotSig if the token has not been signed yet, a ValidationError matching ErrInvTokPrd if the token period
+// has expired, a ValidationError matching ErrInvTokTyp if the token is a refresh token and a ValidationError matching ErrInvSecKey if the entered secret string is invalid corresponding
+// to the signature. Returns nil if the JWT is validated with the entered secret.
 func (this *JWT) Validate(secret string) (err error) {
-	res, err := this.Data()
+	return this.ValidateWithKey(secret)
+}
+
+// ValidateWithKey validates a JWT based on a given key, using the Verifier of the DefaultRegistry
+// for the algorithm in the Header. Asymmetric algorithms require the public key, symmetric
+// algorithms require the secret as a string or a byte slice. To restrict the algorithms a token
+// may be verified with, the key can be bound to them with BindKey.
+// Returns ErrAlgNotAll if the key is not bound to the algorithm in the Header,
+// ErrAlgNotImp if the algorithm in the Header is not implemented yet,
+// ErrTokNotSig if the token has not been signed yet, ErrInvKeyTyp if the key can not be used with the algorithm,
+// a ValidationError matching ErrTokExpd or ErrTokNotAct (and ErrInvTokPrd) if the token period has expired
+// or not started, a ValidationError matching ErrInvTokTyp if the typ (Type) header is TypRefreshJWT
+// and a ValidationError matching ErrInvSecKey if the signature does not match the key.
+// Returns nil if the JWT is validated with the entered key.
+// If the key is a KeyProvider, like a JWKSet, the JWT is validated with ValidateWithKeySet.
+func (this *JWT) ValidateWithKey(key interface{}) (err error) {
+	if provider, ok := key.(KeyProvider); ok {
+		return this.ValidateWithKeySet(provider)
+	}
+	verifier, err := DefaultRegistry.Verifier(this.Header.Algorithm, key)
 	if err != nil {
 		return err
 	}
-	algorithm, exists := Algorithms[this.Header.Algorithm]
-	if !exists {
-		return ErrAlgNotImp
+	return this.ValidateWith(verifier)
+}
+
+// ValidateWithKeySet validates a JWT with the keys of a KeyProvider, like a JWKSet, which are selected
+// by the kid (KeyID) in the Header or, if the Header has no kid, are all keys usable with the algorithm.
+// The JWT is valid, if its Signature matches any of the keys.
+// Returns ErrKeyNotFnd if no key matches the kid and the algorithm in the Header
+// and the same errors as ValidateWithKey otherwise.
+func (this *JWT) ValidateWithKeySet(provider KeyProvider) (err error) {
+	err = verifyWithKeys(DefaultRegistry, this, provider)
+	if err != nil {
+		return err
 	}
-	if !this.IsSigned() {
-		return ErrTokNotSig
+	return this.validateClaims()
+}
+
+// ValidateWith validates a JWT using a Verifier.
+// Returns ErrAlgNotAll if the algorithm in the Header does not match the algorithm of the Verifier
+// and the same errors as ValidateWithKey otherwise.
+func (this *JWT) ValidateWith(verifier Verifier) (err error) {
+	err = this.VerifySignature(verifier)
+	if err != nil {
+		return err
 	}
-	signature, err := algorithm(res, secret)
+	return this.validateClaims()
+}
+
+// VerifySignature validates the Signature of a JWT using a Verifier without checking any claims.
+// Returns the same errors as ValidateWith, except ErrInvTokPrd and ErrInvTokTyp.
+func (this *JWT) VerifySignature(verifier Verifier) (err error) {
+	res, err := this.signingInput()
 	if err != nil {
 		return err
 	}
-	if signature == this.Signature {
-		if this.IsExpired() {
-			return ErrInvTokPrd
-		}
-		return nil
+	if verifier.Algorithm() != this.Header.Algorithm {
+		return ErrAlgNotAll
+	}
+	if !this.IsSigned() {
+		return ErrTokNotSig
 	}
-	return ErrInvSecKey
+	err = verifier.Verify(res, this.Signature)
+	if errors.Is(err, ErrInvSecKey) {
+		return &ValidationError{Err: ErrInvSecKey, Value: this.Header.Algorithm}
+	}
+	return err
 }
 
-// ValidateWithKey validates a JWT based on a given secret string using an asymmetric encryption algorithm
-// Returns ErrAlgNotImp if the algorithm in the Header is not implemented yet,
-// ErrTokNotSig if the token has not been signed yet, ErrInvTokPrd if the token period has expired
-// and ErrInvSecKey if the entered key and/or label is invalid corresponding to the signature.
-// Returns nil if the JWT is validated with the entered key.
-func (this *JWT) ValidateWithKey(label string, key rsa.PrivateKey) (err error) {
-	res, err := this.Data()
+func (this *JWT) validateClaims() error {
+	if isRefreshToken(&this.Header) {
+		return &ValidationError{Err: ErrInvTokTyp, Claim: "typ", Value: this.Header.Type}
+	}
+	now := time.Now()
+	if hasExpired(this.Payload.ExpirationTime, now, 0) {
+		return &ValidationError{Err: ErrTokExpd, Claim: "exp", Value: this.Payload.ExpirationTime.Time, Expected: now}
+	}
+	if isNotActive(this.Payload.NotBefore, now, 0) {
+		return &ValidationError{Err: ErrTokNotAct, Claim: "nbf", Value: this.Payload.NotBefore.Time, Expected: now}
+	}
+	return nil
+}
+
+// ValidateLegacyOAEP validates a JWT, which has been signed by previous versions of gojwt,
+// where the RS256, RS384 and RS512 signatures were created by RSA-OAEP encrypting the token data
+// with a public key. It can be used to accept already issued tokens while migrating to ValidateWithKey.
+// Returns the same errors as ValidateWithKey.
+//
+// Deprecated: Tokens signed with the legacy scheme can not be verified by other JWT libraries,
+// sign new tokens using SignWithKey.
+func (this *JWT) ValidateLegacyOAEP(label string, key rsa.PrivateKey) (err error) {
+	res, err := this.signingInput()
 	if err != nil {
 		return err
 	}
-	algorithm, exists := DecryptionAlgorithms[this.Header.Algorithm]
+	algorithm, exists := legacyDecryptionAlgorithms[this.Header.Algorithm]
 	if !exists {
 		return ErrAlgNotImp
 	}
@@ -136,59 +213,64 @@ func (this *JWT) ValidateWithKey(label string, key rsa.PrivateKey) (err error) {
 	result, err := algorithm(this.Signature, []byte(label), key)
 	if err != nil {
 		if errors.Is(err, rsa.ErrDecryption) {
-			return ErrInvSecKey
+			return &ValidationError{Err: ErrInvSecKey, Value: this.Header.Algorithm}
 		}
 		return err
 	}
 	if res == result {
-		if this.IsExpired() {
-			return ErrInvTokPrd
-		}
-		return nil
+		return this.validateClaims()
 	}
-	return ErrInvSecKey
+	return &ValidationError{Err: ErrInvSecKey, Value: this.Header.Algorithm}
 }
 
 // Sign signs a JWT using a symmetric encryption algorithm and creates the Signature,
 // saved in the JWT. This method overwrites the Signature field in the JWT if it exists.
-// Returns ErrAlgNotImp if the algorithm in the Header is not implemented yet or an asymmetric encryption algorithm.
-// or returns ErrInvTokPrd if the token period has expired before signing.
+// Returns ErrAlgNotImp if the algorithm in the Header is not implemented yet
+// or ErrInvKeyTyp if the algorithm is an asymmetric signature algorithm.
 func (this *JWT) Sign(secret string) (err error) {
-	res, err := this.Data()
+	return this.SignWithKey(secret)
+}
+
+// SignWithKey signs a JWT using a given key and the Signer of the DefaultRegistry for the algorithm in the Header
+// and creates the Signature, saved in the JWT. This method overwrites the Signature field in the JWT if it exists.
+// Asymmetric algorithms require the private key, symmetric algorithms require the secret as a string or a byte slice.
+// If the Header has no KeyID, it is set to the kid of a JWK or the RFC 7638 thumbprint (SHA-256) of an asymmetric key.
+// A KeyID set this way or loaded with LoadJWT is replaced when the JWT is signed again, a KeyID set by the caller is kept.
+// Returns ErrAlgNotImp if the algorithm in the Header is not implemented yet
+// or returns ErrInvKeyTyp if the key can not be used with the algorithm.
+func (this *JWT) SignWithKey(key interface{}) (err error) {
+	signer, err := DefaultRegistry.Signer(this.Header.Algorithm, key)
 	if err != nil {
 		return err
 	}
-	algorithm, exists := Algorithms[this.Header.Algorithm]
-	if !exists {
-		return ErrAlgNotImp
+	if this.Header.KeyID == "" || this.Header.KeyID == this.autoKeyID {
+		this.Header.KeyID = keyID(key)
+		this.autoKeyID = this.Header.KeyID
 	}
-	this.Signature, err = algorithm(res, secret)
-	return err
+	return this.SignWith(signer)
 }
 
-// SignWithKey signs a JWT using an asymmetric encryption algorithm and creates the Signature,
-// saved in the JWT. This method overwrites the Signature field in the JWT if it exists.
-// Returns ErrAlgNotImp if the algorithm in the Header is not implemented yet or a symmetric encryption algorithm
-// or returns ErrInvTokPrd if the token period has expired before signing.
-func (this *JWT) SignWithKey(label string, key rsa.PublicKey) (err error) {
+// SignWith signs a JWT using a Signer and creates the Signature, saved in the JWT.
+// The algorithm in the Header is set to the algorithm of the Signer.
+// This method overwrites the Signature field in the JWT if it exists.
+func (this *JWT) SignWith(signer Signer) (err error) {
+	this.Header.Algorithm = signer.Algorithm()
+	this.rawHeader, this.rawPayload = "", ""
 	res, err := this.Data()
 	if err != nil {
 		return err
 	}
-	algorithm, exists := EncryptionAlgorithms[this.Header.Algorithm]
-	if !exists {
-		return ErrAlgNotImp
-	}
-	this.Signature, err = algorithm(res, []byte(label), key)
+	this.Signature, err = signer.Sign(res)
 	return err
 }
 
 // Parse formats the JWT into a JWT string and returns the result.
 // It requires the token to be signed and the payload and header
 // to be parsed successfully, otherwise it returns ErrTokNotSig.
+// Loaded JWTs are formatted from their original encoded header and payload, until they are signed again.
 // Result = Base64Encode(Header.Json()) + "." + Base64Encode(Payload.Json()) + "." + Signature
 func (this *JWT) Parse() (token string, err error) {
-	data, err := this.Data()
+	data, err := this.signingInput()
 	if err != nil {
 		return "", err
 	}
@@ -220,8 +302,8 @@ func (this *JWT) SignParse(secret string) (token string, err error) {
 }
 
 // SignParseWithKey performs the SignWithKey and Parse operations in one single step.
-func (this *JWT) SignParseWithKey(label string, key rsa.PublicKey) (token string, err error) {
-	err = this.SignWithKey(label, key)
+func (this *JWT) SignParseWithKey(key interface{}) (token string, err error) {
+	err = this.SignWithKey(key)
 	if err != nil {
 		return "", err
 	}
@@ -247,3 +329,12 @@ func (this *JWT) Data() (data string, err error) {
 		".",
 	), nil
 }
+
+// signingInput returns the data the Signature is created for, which are the original encoded
+// segments of a loaded JWT or the formatted Header and Payload otherwise.
+func (this *JWT) signingInput() (data string, err error) {
+	if this.rawHeader != "" || this.rawPayload != "" {
+		return this.rawHeader + "." + this.rawPayload, nil
+	}
+	return this.Data()
+}
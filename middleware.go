@@ -0,0 +1,264 @@
+package gojwt
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+)
+
+// Error codes of the WWW-Authenticate header as specified in RFC 6750 section 3.1.
+const (
+	// ErrCodeInvalidRequest indicates that the request is malformed, like a request with an empty bearer token.
+	ErrCodeInvalidRequest = "invalid_request"
+
+	// ErrCodeInvalidToken indicates that the token is malformed, expired or can not be validated.
+	ErrCodeInvalidToken = "invalid_token"
+
+	// ErrCodeInsufficientScope indicates that the token does not grant the required scopes.
+	ErrCodeInsufficientScope = "insufficient_scope"
+)
+
+type contextKey struct{}
+
+// ContextWithJWT returns a copy of a context holding a JWT, which can be accessed with JWTFromContext.
+func ContextWithJWT(ctx context.Context, jwt *JWT) context.Context {
+	return context.WithValue(ctx, contextKey{}, jwt)
+}
+
+// JWTFromContext returns the JWT of a context, which has been validated by a Middleware,
+// and a bool, whether the context holds a JWT.
+func JWTFromContext(ctx context.Context) (*JWT, bool) {
+	jwt, ok := ctx.Value(contextKey{}).(*JWT)
+	return jwt, ok && jwt != nil
+}
+
+// AuthError describes why a request has been rejected by a Middleware, with the HTTP status code
+// and the error code of the WWW-Authenticate header as specified in RFC 6750.
+type AuthError struct {
+
+	// Status is the HTTP status code of the response, which is 400, 401, 403 or 503.
+	Status int
+
+	// Code is the error code, which is one of ErrCodeInvalidRequest, ErrCodeInvalidToken or ErrCodeInsufficientScope.
+	// It is empty if the request did not contain a token or a store of the Validator failed.
+	Code string
+
+	// Realm is the realm of the protected resource, not required.
+	Realm string
+
+	// Scopes holds the scopes required for accessing the resource, if Code is ErrCodeInsufficientScope.
+	Scopes []string
+
+	// Err is the error of extracting or validating the token.
+	Err error
+}
+
+// Error is the implementation of the error interface.
+func (this *AuthError) Error() string {
+	return this.Err.Error()
+}
+
+// Unwrap returns the error of extracting or validating the token.
+func (this *AuthError) Unwrap() error {
+	return this.Err
+}
+
+// Description returns a description of the error, which does not expose the values of the claims of the token.
+func (this *AuthError) Description() string {
+	var validationErr *ValidationError
+	if errors.As(this.Err, &validationErr) {
+		return validationErr.Err.Error()
+	}
+	return this.Err.Error()
+}
+
+// Challenge returns the value of the WWW-Authenticate header for the Bearer scheme as specified in RFC 6750.
+func (this *AuthError) Challenge() string {
+	var params []string
+	if this.Realm != "" {
+		params = append(params, `realm="`+challengeValue(this.Realm)+`"`)
+	}
+	if this.Code != "" {
+		params = append(params, `error="`+this.Code+`"`)
+		params = append(params, `error_description="`+challengeValue(this.Description())+`"`)
+	}
+	if len(this.Scopes) > 0 {
+		params = append(params, `scope="`+challengeValue(strings.Join(this.Scopes, " "))+`"`)
+	}
+	if len(params) == 0 {
+		return "Bearer"
+	}
+	return "Bearer " + strings.Join(params, ", ")
+}
+
+// ErrorHandler writes the response for a request, which has been rejected by a Middleware.
+type ErrorHandler func(w http.ResponseWriter, r *http.Request, err *AuthError)
+
+// DefaultErrorHandler is the ErrorHandler of a Middleware, which sets the WWW-Authenticate header
+// and responds with the HTTP status code and its text.
+func DefaultErrorHandler(w http.ResponseWriter, r *http.Request, err *AuthError) {
+	w.Header().Set("WWW-Authenticate", err.Challenge())
+	http.Error(w, http.StatusText(err.Status), err.Status)
+}
+
+// Middleware authenticates HTTP requests with JWTs, which are validated with a key and a Validator.
+// The validated JWT is stored in the context of the request and can be accessed with JWTFromContext.
+// A Middleware can not be changed after its creation and is safe for concurrent use.
+type Middleware struct {
+	key          interface{}
+	extractor    TokenExtractor
+	validator    *Validator
+	realm        string
+	scopes       []string
+	optional     bool
+	errorHandler ErrorHandler
+}
+
+// MiddlewareOption configures a Middleware when it is created with NewMiddleware.
+type MiddlewareOption func(middleware *Middleware)
+
+// NewMiddleware creates a new Middleware, which validates tokens with a key and a list of options.
+// The key may also be a KeyProvider, like a JWKSet or a RemoteJWKSet.
+// Without options, tokens are extracted with the BearerExtractor and validated with a Validator without options.
+func NewMiddleware(key interface{}, options ...MiddlewareOption) *Middleware {
+	middleware := &Middleware{
+		key:          key,
+		extractor:    BearerExtractor(),
+		validator:    NewValidator(),
+		errorHandler: DefaultErrorHandler,
+	}
+	for _, option := range options {
+		option(middleware)
+	}
+	return middleware
+}
+
+// UseExtractor sets the TokenExtractor, which extracts the tokens from the requests.
+func UseExtractor(extractor TokenExtractor) MiddlewareOption {
+	return func(middleware *Middleware) {
+		middleware.extractor = extractor
+	}
+}
+
+// UseValidator sets the Validator, which validates the tokens.
+func UseValidator(validator *Validator) MiddlewareOption {
+	return func(middleware *Middleware) {
+		middleware.validator = validator
+	}
+}
+
+// UseRealm sets the realm of the protected resource, which is included in the WWW-Authenticate header.
+func UseRealm(realm string) MiddlewareOption {
+	return func(middleware *Middleware) {
+		middleware.realm = realm
+	}
+}
+
+// RequireScopes sets the scopes, which must all be granted by the scope claim of a token, a space-separated
+// list of scopes as specified in RFC 8693. Tokens missing a scope are rejected with ErrInsScope and the status 403.
+func RequireScopes(scopes ...string) MiddlewareOption {
+	return func(middleware *Middleware) {
+		middleware.scopes = scopes
+	}
+}
+
+// OptionalAuthentication lets requests without a token pass without a JWT in their context.
+// Requests with an invalid token are still rejected.
+func OptionalAuthentication() MiddlewareOption {
+	return func(middleware *Middleware) {
+		middleware.optional = true
+	}
+}
+
+// UseErrorHandler sets the ErrorHandler, which writes the response for rejected requests.
+func UseErrorHandler(handler ErrorHandler) MiddlewareOption {
+	return func(middleware *Middleware) {
+		middleware.errorHandler = handler
+	}
+}
+
+// Handler returns an http.Handler, which authenticates requests before passing them to the next handler.
+// Requests without a token are rejected with the status 401 unless the authentication is optional,
+// malformed requests with the status 400 and ErrCodeInvalidRequest, requests with an invalid token
+// with the status 401 and ErrCodeInvalidToken and requests with a token missing a required scope
+// with the status 403 and ErrCodeInsufficientScope. If a store of the Validator fails, like a RevocationStore,
+// requests are rejected with the status 503 and without an error code, so clients do not discard their tokens.
+func (this *Middleware) Handler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		jwt, err := this.Authenticate(r)
+		if errors.Is(err, ErrTokMis) && this.optional {
+			next.ServeHTTP(w, r)
+			return
+		}
+		if err != nil {
+			var authErr *AuthError
+			errors.As(err, &authErr)
+			this.errorHandler(w, r, authErr)
+			return
+		}
+		next.ServeHTTP(w, r.WithContext(ContextWithJWT(r.Context(), jwt)))
+	})
+}
+
+// Authenticate extracts the token of a request and validates it, without writing a response.
+// Returns an AuthError matching ErrTokMis if the request has no token, ErrBadReq if the request is malformed,
+// the errors of the Parse method of Validator or ErrInsScope if the token is missing a required scope.
+func (this *Middleware) Authenticate(r *http.Request) (*JWT, error) {
+	token, err := this.extractor(r)
+	if err != nil {
+		return nil, this.authError(err)
+	}
+	jwt, err := this.validator.Parse(token, this.key)
+	if err != nil {
+		return nil, this.authError(err)
+	}
+	if !grantsScopes(jwt, this.scopes) {
+		return nil, this.authError(ErrInsScope)
+	}
+	return jwt, nil
+}
+
+func (this *Middleware) authError(err error) *AuthError {
+	res := &AuthError{
+		Status: http.StatusUnauthorized,
+		Code:   ErrCodeInvalidToken,
+		Realm:  this.realm,
+		Err:    err,
+	}
+	switch {
+	case errors.Is(err, ErrTokMis):
+		res.Code = ""
+	case errors.Is(err, ErrBadReq):
+		res.Status, res.Code = http.StatusBadRequest, ErrCodeInvalidRequest
+	case errors.Is(err, ErrInsScope):
+		res.Status, res.Code, res.Scopes = http.StatusForbidden, ErrCodeInsufficientScope, this.scopes
+	case errors.Is(err, ErrStoreUnav):
+		res.Status, res.Code = http.StatusServiceUnavailable, ""
+	}
+	return res
+}
+
+func grantsScopes(jwt *JWT, scopes []string) bool {
+	if len(scopes) == 0 {
+		return true
+	}
+	scope, _ := jwt.Payload.GetCustom("scope").(string)
+	granted := strings.Fields(scope)
+	for _, required := range scopes {
+		if !contains(granted, required) {
+			return false
+		}
+	}
+	return true
+}
+
+// challengeValue removes the characters, which are not allowed in the parameters of the WWW-Authenticate header.
+func challengeValue(value string) string {
+	return strings.Map(func(r rune) rune {
+		if r < 0x20 || r > 0x7E || r == '"' || r == '\\' {
+			return -1
+		}
+		return r
+	}, value)
+}
@@ -0,0 +1,170 @@
+package gojwt_test
+
+import (
+	"github.com/tobyguelly/gojwt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestMiddleware_Handler(t *testing.T) {
+	sign := func(jwt gojwt.JWT) string {
+		token, err := jwt.SignParse("secret")
+		if err != nil {
+			t.Errorf("Failed test because of error: %s", err.Error())
+			t.FailNow()
+		}
+		return token
+	}
+	valid := gojwt.NewJWT()
+	valid.Payload.Subject = "Joe"
+	scoped := gojwt.NewJWT()
+	scoped.Payload.Subject = "Joe"
+	scoped.Payload.SetCustom("scope", "read write")
+	revocable := gojwt.NewJWT()
+	revocable.Payload.Subject = "Joe"
+	revocable.Payload.JWTID = "a"
+	expired := gojwt.NewJWT()
+	expired.Payload.ExpirationTime = gojwt.Wrap(time.Now().Add(-time.Hour))
+	pair, err := gojwt.NewTokenPairIssuer("secret", "secret").Issue(gojwt.WithBuilder().Subject("Joe"))
+	if err != nil {
+		t.Errorf("Failed test because of error: %s", err.Error())
+		t.FailNow()
+	}
+	tests := []struct {
+		Authorization     []string
+		Options           []gojwt.MiddlewareOption
+		ExpectedStatus    int
+		ExpectedChallenge string
+		ExpectedSubject   string
+	}{
+		{
+			Authorization:   []string{"Bearer " + sign(valid)},
+			ExpectedStatus:  http.StatusOK,
+			ExpectedSubject: "Joe",
+		},
+		{
+			Authorization:   []string{"bearer " + sign(valid)},
+			ExpectedStatus:  http.StatusOK,
+			ExpectedSubject: "Joe",
+		},
+		{
+			Authorization:     nil,
+			ExpectedStatus:    http.StatusUnauthorized,
+			ExpectedChallenge: `Bearer`,
+		},
+		{
+			Authorization:     []string{"Basic am9lOnNlY3JldA=="},
+			Options:           []gojwt.MiddlewareOption{gojwt.UseRealm("example")},
+			ExpectedStatus:    http.StatusUnauthorized,
+			ExpectedChallenge: `Bearer realm="example"`,
+		},
+		{
+			Authorization:     []string{"Bearer"},
+			ExpectedStatus:    http.StatusBadRequest,
+			ExpectedChallenge: `Bearer error="invalid_request", error_description="MALFORMED TOKEN REQUEST"`,
+		},
+		{
+			Authorization:     []string{"Bearer " + sign(valid), "Bearer " + sign(valid)},
+			ExpectedStatus:    http.StatusBadRequest,
+			ExpectedChallenge: `Bearer error="invalid_request", error_description="MALFORMED TOKEN REQUEST"`,
+		},
+		{
+			Authorization:     []string{"Bearer " + sign(expired)},
+			Options:           []gojwt.MiddlewareOption{gojwt.UseRealm("example")},
+			ExpectedStatus:    http.StatusUnauthorized,
+			ExpectedChallenge: `Bearer realm="example", error="invalid_token", error_description="TOKEN EXPIRED"`,
+		},
+		{
+			Authorization:     []string{"Bearer " + sign(valid)},
+			Options:           []gojwt.MiddlewareOption{gojwt.UseValidator(gojwt.NewValidator(gojwt.ExpectIssuers("foo")))},
+			ExpectedStatus:    http.StatusUnauthorized,
+			ExpectedChallenge: `Bearer error="invalid_token", error_description="INVALID TOKEN ISSUER"`,
+		},
+		{
+			Authorization:   []string{"Bearer " + pair.AccessToken},
+			ExpectedStatus:  http.StatusOK,
+			ExpectedSubject: "Joe",
+		},
+		{
+			Authorization:     []string{"Bearer " + pair.RefreshToken},
+			ExpectedStatus:    http.StatusUnauthorized,
+			ExpectedChallenge: `Bearer error="invalid_token", error_description="INVALID TOKEN TYPE"`,
+		},
+		{
+			Authorization: []string{"Bearer " + sign(revocable)},
+			Options: []gojwt.MiddlewareOption{gojwt.UseValidator(gojwt.NewValidator(
+				gojwt.CheckRevocation(unavailableRevocationStore{}),
+			))},
+			ExpectedStatus:    http.StatusServiceUnavailable,
+			ExpectedChallenge: `Bearer`,
+		},
+		{
+			Authorization: []string{"Bearer " + sign(valid)},
+			Options: []gojwt.MiddlewareOption{gojwt.UseValidator(gojwt.NewValidator(
+				gojwt.CheckCutoffs(unavailableCutoffStore{}, false),
+			))},
+			ExpectedStatus:    http.StatusServiceUnavailable,
+			ExpectedChallenge: `Bearer`,
+		},
+		{
+			Authorization:   []string{"Bearer " + sign(scoped)},
+			Options:         []gojwt.MiddlewareOption{gojwt.RequireScopes("read")},
+			ExpectedStatus:  http.StatusOK,
+			ExpectedSubject: "Joe",
+		},
+		{
+			Authorization:     []string{"Bearer " + sign(scoped)},
+			Options:           []gojwt.MiddlewareOption{gojwt.RequireScopes("read", "admin")},
+			ExpectedStatus:    http.StatusForbidden,
+			ExpectedChallenge: `Bearer error="insufficient_scope", error_description="INSUFFICIENT SCOPE", scope="read admin"`,
+		},
+		{
+			Authorization:   []string{"Token " + sign(valid)},
+			Options:         []gojwt.MiddlewareOption{gojwt.UseExtractor(gojwt.HeaderExtractor("Authorization", "Token "))},
+			ExpectedStatus:  http.StatusOK,
+			ExpectedSubject: "Joe",
+		},
+		{
+			Authorization:  nil,
+			Options:        []gojwt.MiddlewareOption{gojwt.OptionalAuthentication()},
+			ExpectedStatus: http.StatusOK,
+		},
+		{
+			Authorization:     []string{"Bearer " + sign(expired)},
+			Options:           []gojwt.MiddlewareOption{gojwt.OptionalAuthentication()},
+			ExpectedStatus:    http.StatusUnauthorized,
+			ExpectedChallenge: `Bearer error="invalid_token", error_description="TOKEN EXPIRED"`,
+		},
+		{
+			Authorization: []string{"Bearer " + sign(expired)},
+			Options: []gojwt.MiddlewareOption{gojwt.UseErrorHandler(func(w http.ResponseWriter, r *http.Request, err *gojwt.AuthError) {
+				w.WriteHeader(http.StatusTeapot)
+			})},
+			ExpectedStatus: http.StatusTeapot,
+		},
+	}
+	for i, test := range tests {
+		var subject string
+		handler := gojwt.NewMiddleware("secret", test.Options...).Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if jwt, ok := gojwt.JWTFromContext(r.Context()); ok {
+				subject = jwt.Payload.Subject
+			}
+		}))
+		request := httptest.NewRequest(http.MethodGet, "/", nil)
+		for _, value := range test.Authorization {
+			request.Header.Add("Authorization", value)
+		}
+		recorder := httptest.NewRecorder()
+		handler.ServeHTTP(recorder, request)
+		challenge := recorder.Header().Get("WWW-Authenticate")
+		if recorder.Code == test.ExpectedStatus && challenge == test.ExpectedChallenge && subject == test.ExpectedSubject {
+			t.Logf("Passed %d/%d tests!", i+1, len(tests))
+		} else {
+			t.Errorf("Output and expected output did not match: %v\nFound:\t\t%d %s %s\nExpected:\t%d %s %s",
+				test.Authorization, recorder.Code, challenge, subject, test.ExpectedStatus, test.ExpectedChallenge, test.ExpectedSubject,
+			)
+		}
+	}
+}
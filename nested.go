@@ -0,0 +1,116 @@
+package gojwt
+
+import (
+	"strings"
+)
+
+// NestedError describes a failure of a nested JWT, which is a signed JWT encrypted as the plaintext of a JWE.
+// It distinguishes failures of the outer JWE, like a wrong decryption key, from failures of the inner JWT,
+// like an invalid signature or an expired token, and can be compared to the wrapped error with errors.Is.
+type NestedError struct {
+
+	// Inner is true if the inner JWT failed, and false if the outer JWE failed.
+	Inner bool
+
+	// Err is the error of the failed token.
+	Err error
+}
+
+// Error is the implementation of the error interface.
+func (this *NestedError) Error() string {
+	if this.Inner {
+		return "INNER JWT: " + this.Err.Error()
+	}
+	return "OUTER JWE: " + this.Err.Error()
+}
+
+// Unwrap returns the error of the failed token.
+func (this *NestedError) Unwrap() error {
+	return this.Err
+}
+
+// EncryptNested encrypts a signed JWT into a nested JWT, which is a JWE with the content type "JWT",
+// using a key management and a content encryption algorithm and returns the JWE string.
+// Returns a NestedError with ErrTokNotSig if the JWT has not been signed yet,
+// or a NestedError with the same errors as the Encrypt method of JWE.
+func (this *JWT) EncryptNested(alg, enc string, key interface{}) (string, error) {
+	if !this.IsSigned() || strings.EqualFold(this.Header.Algorithm, "none") {
+		return "", &NestedError{Inner: true, Err: ErrTokNotSig}
+	}
+	token, err := this.Parse()
+	if err != nil {
+		return "", &NestedError{Inner: true, Err: err}
+	}
+	jwe := NewJWE(alg, enc)
+	jwe.Header.ContentType = TypJWT
+	token, err = jwe.EncryptParse([]byte(token), key)
+	if err != nil {
+		return "", &NestedError{Err: err}
+	}
+	return token, nil
+}
+
+// SignEncryptNested signs a JWT with a signing key like SignWithKey and encrypts it into a nested JWT
+// like EncryptNested in one single step.
+func (this *JWT) SignEncryptNested(signingKey interface{}, alg, enc string, encryptionKey interface{}) (string, error) {
+	err := this.SignWithKey(signingKey)
+	if err != nil {
+		return "", &NestedError{Inner: true, Err: err}
+	}
+	return this.EncryptNested(alg, enc, encryptionKey)
+}
+
+// LoadNested decrypts a nested JWT and loads the inner JWT without validating it.
+// Returns a NestedError with ErrBadJWETok if the token is not a JWE, ErrInvTokCty if the content type
+// of the JWE is not "JWT", the errors of the Decrypt method of JWE, or a NestedError marked as Inner
+// with ErrBadJWTTok if the plaintext is not a JWT or ErrTokNotSig if the inner JWT is not signed.
+func LoadNested(token string, decryptionKey interface{}) (*JWT, error) {
+	jwe, err := LoadJWE(token)
+	if err != nil {
+		return nil, &NestedError{Err: err}
+	}
+	if !strings.EqualFold(jwe.Header.ContentType, TypJWT) {
+		return nil, &NestedError{Err: ErrInvTokCty}
+	}
+	plaintext, err := jwe.Decrypt(decryptionKey)
+	if err != nil {
+		return nil, &NestedError{Err: err}
+	}
+	jwt, err := LoadJWT(string(plaintext))
+	if err != nil {
+		return nil, &NestedError{Inner: true, Err: ErrBadJWTTok}
+	}
+	if !jwt.IsSigned() || strings.EqualFold(jwt.Header.Algorithm, "none") {
+		return nil, &NestedError{Inner: true, Err: ErrTokNotSig}
+	}
+	return jwt, nil
+}
+
+// ValidateNested decrypts a nested JWT with a decryption key and validates the inner JWT with a verification key
+// like ValidateWithKey. Returns the same errors as LoadNested, or a NestedError marked as Inner with the
+// errors of ValidateWithKey.
+func ValidateNested(token string, decryptionKey, verificationKey interface{}) (*JWT, error) {
+	jwt, err := LoadNested(token, decryptionKey)
+	if err != nil {
+		return nil, err
+	}
+	err = jwt.ValidateWithKey(verificationKey)
+	if err != nil {
+		return jwt, &NestedError{Inner: true, Err: err}
+	}
+	return jwt, nil
+}
+
+// ParseNested decrypts a nested JWT with a decryption key and validates the inner JWT with a verification key
+// like Validate. Returns the same errors as LoadNested, or a NestedError marked as Inner with the errors of Validate.
+func (this *Validator) ParseNested(token string, decryptionKey, verificationKey interface{}) (*JWT, error) {
+	jwt, err := LoadNested(token, decryptionKey)
+	if err != nil {
+		return nil, err
+	}
+	err = this.Validate(jwt, verificationKey)
+	if err != nil {
+		return jwt, &NestedError{Inner: true, Err: err}
+	}
+	return jwt, nil
+}
@@ -0,0 +1,188 @@
+package gojwt_test
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"errors"
+	"github.com/tobyguelly/gojwt"
+	"testing"
+	"time"
+)
+
+func TestValidateNested(t *testing.T) {
+	encryptionKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Errorf("Failed test because of error: %s", err.Error())
+		t.FailNow()
+	}
+	otherKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Errorf("Failed test because of error: %s", err.Error())
+		t.FailNow()
+	}
+	secret := []byte("0123456789abcdef0123456789abcdef")
+	nested := func(jwt gojwt.JWT, alg string, key interface{}) string {
+		token, err := jwt.SignEncryptNested("secret", alg, gojwt.EncA256GCM, key)
+		if err != nil {
+			t.Errorf("Failed test because of error: %s", err.Error())
+			t.FailNow()
+		}
+		return token
+	}
+	expired := gojwt.NewJWT()
+	expired.Payload.ExpirationTime = gojwt.Wrap(time.Now().Add(-time.Hour))
+	plain := gojwt.NewJWE(gojwt.AlgDir, gojwt.EncA256GCM)
+	plainToken, err := plain.EncryptParse([]byte("Live long and prosper."), secret)
+	if err != nil {
+		t.Errorf("Failed test because of error: %s", err.Error())
+		t.FailNow()
+	}
+	plain = gojwt.NewJWE(gojwt.AlgDir, gojwt.EncA256GCM)
+	plain.Header.ContentType = gojwt.TypJWT
+	notJWTToken, err := plain.EncryptParse([]byte("Live long and prosper."), secret)
+	if err != nil {
+		t.Errorf("Failed test because of error: %s", err.Error())
+		t.FailNow()
+	}
+	unsigned := gojwt.NewJWT()
+	unsigned.Header.Algorithm = "none"
+	unsignedData, err := unsigned.Data()
+	if err != nil {
+		t.Errorf("Failed test because of error: %s", err.Error())
+		t.FailNow()
+	}
+	plain = gojwt.NewJWE(gojwt.AlgDir, gojwt.EncA256GCM)
+	plain.Header.ContentType = gojwt.TypJWT
+	unsignedToken, err := plain.EncryptParse([]byte(unsignedData+"."), secret)
+	if err != nil {
+		t.Errorf("Failed test because of error: %s", err.Error())
+		t.FailNow()
+	}
+	tests := []struct {
+		Input           string
+		DecryptionKey   interface{}
+		VerificationKey interface{}
+		ExpectedInner   bool
+		ExpectedError   error
+	}{
+		{
+			Input:           nested(gojwt.NewJWT(), gojwt.AlgRSAOAEP256, &encryptionKey.PublicKey),
+			DecryptionKey:   encryptionKey,
+			VerificationKey: "secret",
+			ExpectedError:   nil,
+		},
+		{
+			Input:           nested(gojwt.NewJWT(), gojwt.AlgDir, secret),
+			DecryptionKey:   secret,
+			VerificationKey: "secret",
+			ExpectedError:   nil,
+		},
+		{
+			Input:           nested(gojwt.NewJWT(), gojwt.AlgRSAOAEP256, &encryptionKey.PublicKey),
+			DecryptionKey:   otherKey,
+			VerificationKey: "secret",
+			ExpectedInner:   false,
+			ExpectedError:   gojwt.ErrDecFail,
+		},
+		{
+			Input:           nested(gojwt.NewJWT(), gojwt.AlgRSAOAEP256, &encryptionKey.PublicKey),
+			DecryptionKey:   encryptionKey,
+			VerificationKey: "wrong",
+			ExpectedInner:   true,
+			ExpectedError:   gojwt.ErrInvSecKey,
+		},
+		{
+			Input:           nested(expired, gojwt.AlgDir, secret),
+			DecryptionKey:   secret,
+			VerificationKey: "secret",
+			ExpectedInner:   true,
+			ExpectedError:   gojwt.ErrTokExpd,
+		},
+		{
+			Input:           plainToken,
+			DecryptionKey:   secret,
+			VerificationKey: "secret",
+			ExpectedInner:   false,
+			ExpectedError:   gojwt.ErrInvTokCty,
+		},
+		{
+			Input:           notJWTToken,
+			DecryptionKey:   secret,
+			VerificationKey: "secret",
+			ExpectedInner:   true,
+			ExpectedError:   gojwt.ErrBadJWTTok,
+		},
+		{
+			Input:           unsignedToken,
+			DecryptionKey:   secret,
+			VerificationKey: "secret",
+			ExpectedInner:   true,
+			ExpectedError:   gojwt.ErrTokNotSig,
+		},
+		{
+			Input:           "eyJhbGciOiJIUzI1NiJ9.e30.c2lnbmF0dXJl",
+			DecryptionKey:   secret,
+			VerificationKey: "secret",
+			ExpectedInner:   false,
+			ExpectedError:   gojwt.ErrBadJWETok,
+		},
+	}
+	for i, test := range tests {
+		_, err := gojwt.ValidateNested(test.Input, test.DecryptionKey, test.VerificationKey)
+		var nestedErr *gojwt.NestedError
+		inner := errors.As(err, &nestedErr) && nestedErr.Inner
+		if errors.Is(err, test.ExpectedError) && inner == test.ExpectedInner {
+			t.Logf("Passed %d/%d tests!", i+1, len(tests))
+		} else {
+			t.Errorf("Output and expected output did not match: %s\nFound:\t\t%v %v\nExpected:\t%v %v",
+				test.Input, inner, err, test.ExpectedInner, test.ExpectedError,
+			)
+		}
+	}
+}
+
+func TestJWT_EncryptNested(t *testing.T) {
+	secret := []byte("0123456789abcdef0123456789abcdef")
+	signed := gojwt.NewJWT()
+	err := signed.Sign("secret")
+	if err != nil {
+		t.Errorf("Failed test because of error: %s", err.Error())
+		t.FailNow()
+	}
+	unsigned := gojwt.NewJWT()
+	tests := []struct {
+		Input         gojwt.JWT
+		Validator     *gojwt.Validator
+		ExpectedError error
+	}{
+		{
+			Input:         signed,
+			Validator:     gojwt.NewValidator(),
+			ExpectedError: nil,
+		},
+		{
+			Input:         signed,
+			Validator:     gojwt.NewValidator(gojwt.ExpectIssuers("foo")),
+			ExpectedError: gojwt.ErrInvTokIss,
+		},
+		{
+			Input:         unsigned,
+			Validator:     gojwt.NewValidator(),
+			ExpectedError: gojwt.ErrTokNotSig,
+		},
+	}
+	for i, test := range tests {
+		token, err := test.Input.EncryptNested(gojwt.AlgA256KW, gojwt.EncA128CBCHS256, secret)
+		var res *gojwt.JWT
+		if err == nil {
+			res, err = test.Validator.ParseNested(token, secret, "secret")
+		}
+		if errors.Is(err, test.ExpectedError) && (err != nil || res.Signature == test.Input.Signature) {
+			t.Logf("Passed %d/%d tests!", i+1, len(tests))
+		} else {
+			t.Errorf("Output and expected output did not match: %v\nFound:\t\t%v\nExpected:\t%v",
+				test.Input, err, test.ExpectedError,
+			)
+		}
+	}
+}
@@ -16,8 +16,8 @@ type Payload struct {
 	// Subject is the subject claim in the JWT token.
 	Subject string `json:"sub,omitempty"`
 
-	// Audience is the audience claim in the JWT token.
-	Audience string `json:"aud,omitempty"`
+	// Audience is the audience claim in the JWT token, which is either a single string or an array of strings.
+	Audience *Audience `json:"aud,omitempty"`
 
 	// ExpirationTime is the expiration time claim in the JWT token.
 	ExpirationTime *Time `json:"exp,omitempty"`
@@ -57,6 +57,28 @@ func (this *Payload) GetCustom(key string) interface{} {
 	return this.Custom[key]
 }
 
+// HasClaim returns a bool, whether the Payload contains a claim identified by its name, like "iss" or a custom key.
+func (this *Payload) HasClaim(name string) bool {
+	switch name {
+	case "iss":
+		return this.Issuer != ""
+	case "sub":
+		return this.Subject != ""
+	case "aud":
+		return !this.Audience.IsEmpty()
+	case "exp":
+		return this.ExpirationTime != nil
+	case "nbf":
+		return this.NotBefore != nil
+	case "iat":
+		return this.IssuedAt != nil
+	case "jti":
+		return this.JWTID != ""
+	}
+	_, exists := this.Custom[name]
+	return exists
+}
+
 // Json formats the Payload into JSON format.
 func (this *Payload) Json() (string, error) {
 	preRes, err := json.Marshal(this)
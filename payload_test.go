@@ -111,3 +111,51 @@ func TestPayload_Json(t *testing.T) {
 		}
 	}
 }
+
+func TestPayload_HasClaim(t *testing.T) {
+	tests := []struct {
+		Input          gojwt.Payload
+		Claim          string
+		ExpectedOutput bool
+	}{
+		{
+			Input:          gojwt.Payload{Issuer: "1234"},
+			Claim:          "iss",
+			ExpectedOutput: true,
+		},
+		{
+			Input:          gojwt.Payload{Issuer: "1234"},
+			Claim:          "exp",
+			ExpectedOutput: false,
+		},
+		{
+			Input:          gojwt.Payload{IssuedAt: gojwt.Unix(0)},
+			Claim:          "iat",
+			ExpectedOutput: true,
+		},
+		{
+			Input: gojwt.Payload{
+				Custom: map[string]interface{}{
+					"hello": "world",
+				},
+			},
+			Claim:          "hello",
+			ExpectedOutput: true,
+		},
+		{
+			Input:          gojwt.Payload{},
+			Claim:          "hello",
+			ExpectedOutput: false,
+		},
+	}
+	for i, test := range tests {
+		res := test.Input.HasClaim(test.Claim)
+		if res == test.ExpectedOutput {
+			t.Logf("Passed %d/%d tests!", i+1, len(tests))
+		} else {
+			t.Errorf("Output and expected output did not match: %s\nFound:\t\t%t\nExpected:\t%t",
+				test.Claim, res, test.ExpectedOutput,
+			)
+		}
+	}
+}
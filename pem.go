@@ -0,0 +1,148 @@
+package gojwt
+
+import (
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"strings"
+)
+
+// LoadKeyPEM loads the first key of a PEM encoded blob, which may hold a PKCS #1 RSA key, a PKCS #8 private key,
+// a SEC 1 EC private key, a PKIX public key or a certificate, whose public key is returned. The key type is
+// detected automatically and the key is returned as one of the key types accepted by SignWithKey and ValidateWithKey.
+// Blocks with EC parameters are skipped. Returns an error wrapping ErrEncKey if the key is encrypted
+// or an error wrapping ErrInvPEM if the blob holds no key or the key type is not supported.
+func LoadKeyPEM(data []byte) (interface{}, error) {
+	for {
+		var block *pem.Block
+		block, data = pem.Decode(data)
+		if block == nil {
+			return nil, fmt.Errorf("%w: no PEM encoded key found", ErrInvPEM)
+		}
+		if block.Type == "EC PARAMETERS" {
+			continue
+		}
+		if block.Type == "ENCRYPTED PRIVATE KEY" || strings.Contains(block.Headers["Proc-Type"], "ENCRYPTED") {
+			return nil, fmt.Errorf("%w: %s block", ErrEncKey, block.Type)
+		}
+		var key interface{}
+		var err error
+		switch block.Type {
+		case "RSA PRIVATE KEY":
+			key, err = x509.ParsePKCS1PrivateKey(block.Bytes)
+		case "RSA PUBLIC KEY":
+			key, err = x509.ParsePKCS1PublicKey(block.Bytes)
+		case "EC PRIVATE KEY":
+			key, err = x509.ParseECPrivateKey(block.Bytes)
+		case "PRIVATE KEY":
+			key, err = x509.ParsePKCS8PrivateKey(block.Bytes)
+		case "PUBLIC KEY":
+			key, err = x509.ParsePKIXPublicKey(block.Bytes)
+		case "CERTIFICATE":
+			key, err = parseCertificateKey(block.Bytes)
+		default:
+			return nil, fmt.Errorf("%w: unsupported block type %q", ErrInvPEM, block.Type)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s", ErrInvPEM, err.Error())
+		}
+		return supportedPEMKey(key)
+	}
+}
+
+// LoadKeyDER loads a DER encoded key, which may be a PKCS #1 RSA key, a PKCS #8 private key, a SEC 1 EC private key,
+// a PKIX public key or a certificate, whose public key is returned. The encoding is detected automatically.
+// Returns an error wrapping ErrInvPEM if the key can not be parsed or the key type is not supported.
+func LoadKeyDER(der []byte) (interface{}, error) {
+	parsers := []func([]byte) (interface{}, error){
+		x509.ParsePKCS8PrivateKey,
+		func(der []byte) (interface{}, error) { return x509.ParsePKCS1PrivateKey(der) },
+		func(der []byte) (interface{}, error) { return x509.ParseECPrivateKey(der) },
+		x509.ParsePKIXPublicKey,
+		func(der []byte) (interface{}, error) { return x509.ParsePKCS1PublicKey(der) },
+		parseCertificateKey,
+	}
+	for _, parse := range parsers {
+		key, err := parse(der)
+		if err == nil {
+			return supportedPEMKey(key)
+		}
+	}
+	return nil, fmt.Errorf("%w: unknown DER encoding", ErrInvPEM)
+}
+
+// EncodeKeyPEM encodes a key into PEM format, where private keys are encoded as PKCS #8 "PRIVATE KEY"
+// blocks and public keys as PKIX "PUBLIC KEY" blocks. The key may also be a JWK or a BoundKey.
+// Returns ErrInvKeyTyp if the key type is not supported.
+func EncodeKeyPEM(key interface{}) ([]byte, error) {
+	der, err := EncodeKeyDER(key)
+	if err != nil {
+		return nil, err
+	}
+	blockType := "PUBLIC KEY"
+	if isPrivateKey(key) {
+		blockType = "PRIVATE KEY"
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: blockType, Bytes: der}), nil
+}
+
+// EncodeKeyDER encodes a key into DER format, where private keys are encoded as PKCS #8
+// and public keys as PKIX. The key may also be a JWK or a BoundKey.
+// Returns ErrInvKeyTyp if the key type is not supported.
+func EncodeKeyDER(key interface{}) ([]byte, error) {
+	key = unwrapKey(key)
+	if _, err := supportedPEMKey(key); err != nil {
+		return nil, ErrInvKeyTyp
+	}
+	if isPrivateKey(key) {
+		return x509.MarshalPKCS8PrivateKey(key)
+	}
+	return x509.MarshalPKIXPublicKey(key)
+}
+
+func parseCertificateKey(der []byte) (interface{}, error) {
+	certificate, err := x509.ParseCertificate(der)
+	if err != nil {
+		return nil, err
+	}
+	return certificate.PublicKey, nil
+}
+
+func supportedPEMKey(key interface{}) (interface{}, error) {
+	if isNilKey(key) {
+		return nil, fmt.Errorf("%w: unsupported key type %T", ErrInvPEM, key)
+	}
+	switch key.(type) {
+	case *rsa.PrivateKey, *rsa.PublicKey, ed25519.PrivateKey, ed25519.PublicKey:
+		return key, nil
+	case *ecdsa.PrivateKey, *ecdsa.PublicKey:
+		if _, crv := keyType(key); crv != "" {
+			return key, nil
+		}
+	}
+	return nil, fmt.Errorf("%w: unsupported key type %T", ErrInvPEM, key)
+}
+
+func unwrapKey(key interface{}) interface{} {
+	if isNilKey(key) {
+		return nil
+	}
+	switch k := key.(type) {
+	case *BoundKey:
+		return unwrapKey(k.Key)
+	case *JWK:
+		return k.Key
+	}
+	return key
+}
+
+func isPrivateKey(key interface{}) bool {
+	switch unwrapKey(key).(type) {
+	case *rsa.PrivateKey, *ecdsa.PrivateKey, ed25519.PrivateKey:
+		return true
+	}
+	return false
+}
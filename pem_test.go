@@ -0,0 +1,240 @@
+package gojwt_test
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"errors"
+	"github.com/tobyguelly/gojwt"
+	"math/big"
+	"testing"
+	"time"
+)
+
+func keysEqual(expected, key interface{}) bool {
+	switch k := expected.(type) {
+	case interface{ Equal(crypto.PrivateKey) bool }:
+		return k.Equal(key)
+	case interface{ Equal(crypto.PublicKey) bool }:
+		return k.Equal(key)
+	}
+	return false
+}
+
+func TestLoadKeyPEM(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Errorf("Failed test because of error: %s", err.Error())
+		t.FailNow()
+	}
+	ecKey, err := ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+	if err != nil {
+		t.Errorf("Failed test because of error: %s", err.Error())
+		t.FailNow()
+	}
+	unsupportedKey, err := ecdsa.GenerateKey(elliptic.P224(), rand.Reader)
+	if err != nil {
+		t.Errorf("Failed test because of error: %s", err.Error())
+		t.FailNow()
+	}
+	_, edPrivateKey, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Errorf("Failed test because of error: %s", err.Error())
+		t.FailNow()
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "gojwt"},
+		NotBefore:    time.Now(),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	certificate, err := x509.CreateCertificate(rand.Reader, template, template, &ecKey.PublicKey, ecKey)
+	if err != nil {
+		t.Errorf("Failed test because of error: %s", err.Error())
+		t.FailNow()
+	}
+	marshal := func(blockType string, der []byte) []byte {
+		return pem.EncodeToMemory(&pem.Block{Type: blockType, Bytes: der})
+	}
+	sec1, err := x509.MarshalECPrivateKey(ecKey)
+	if err != nil {
+		t.Errorf("Failed test because of error: %s", err.Error())
+		t.FailNow()
+	}
+	pkcs8, err := x509.MarshalPKCS8PrivateKey(edPrivateKey)
+	if err != nil {
+		t.Errorf("Failed test because of error: %s", err.Error())
+		t.FailNow()
+	}
+	pkixKey, err := x509.MarshalPKIXPublicKey(&rsaKey.PublicKey)
+	if err != nil {
+		t.Errorf("Failed test because of error: %s", err.Error())
+		t.FailNow()
+	}
+	unsupported, err := x509.MarshalPKIXPublicKey(&unsupportedKey.PublicKey)
+	if err != nil {
+		t.Errorf("Failed test because of error: %s", err.Error())
+		t.FailNow()
+	}
+	ecParameters := marshal("EC PARAMETERS", []byte{6, 5, 43, 129, 4, 0, 34})
+	tests := []struct {
+		Input          []byte
+		ExpectedOutput interface{}
+		ExpectedError  error
+	}{
+		{
+			Input:          marshal("RSA PRIVATE KEY", x509.MarshalPKCS1PrivateKey(rsaKey)),
+			ExpectedOutput: rsaKey,
+			ExpectedError:  nil,
+		},
+		{
+			Input:          marshal("RSA PUBLIC KEY", x509.MarshalPKCS1PublicKey(&rsaKey.PublicKey)),
+			ExpectedOutput: &rsaKey.PublicKey,
+			ExpectedError:  nil,
+		},
+		{
+			Input:          append(ecParameters, marshal("EC PRIVATE KEY", sec1)...),
+			ExpectedOutput: ecKey,
+			ExpectedError:  nil,
+		},
+		{
+			Input:          marshal("PRIVATE KEY", pkcs8),
+			ExpectedOutput: edPrivateKey,
+			ExpectedError:  nil,
+		},
+		{
+			Input:          marshal("PUBLIC KEY", pkixKey),
+			ExpectedOutput: &rsaKey.PublicKey,
+			ExpectedError:  nil,
+		},
+		{
+			Input:          marshal("CERTIFICATE", certificate),
+			ExpectedOutput: &ecKey.PublicKey,
+			ExpectedError:  nil,
+		},
+		{
+			Input:          marshal("PUBLIC KEY", unsupported),
+			ExpectedOutput: nil,
+			ExpectedError:  gojwt.ErrInvPEM,
+		},
+		{
+			Input:          marshal("ENCRYPTED PRIVATE KEY", []byte{0}),
+			ExpectedOutput: nil,
+			ExpectedError:  gojwt.ErrEncKey,
+		},
+		{
+			Input: pem.EncodeToMemory(&pem.Block{
+				Type:    "RSA PRIVATE KEY",
+				Headers: map[string]string{"Proc-Type": "4,ENCRYPTED", "DEK-Info": "AES-256-CBC,00000000000000000000000000000000"},
+				Bytes:   x509.MarshalPKCS1PrivateKey(rsaKey),
+			}),
+			ExpectedOutput: nil,
+			ExpectedError:  gojwt.ErrEncKey,
+		},
+		{
+			Input:          marshal("DSA PRIVATE KEY", []byte{0}),
+			ExpectedOutput: nil,
+			ExpectedError:  gojwt.ErrInvPEM,
+		},
+		{
+			Input:          marshal("PRIVATE KEY", []byte{0}),
+			ExpectedOutput: nil,
+			ExpectedError:  gojwt.ErrInvPEM,
+		},
+		{
+			Input:          []byte("not a PEM blob"),
+			ExpectedOutput: nil,
+			ExpectedError:  gojwt.ErrInvPEM,
+		},
+	}
+	for i, test := range tests {
+		res, err := gojwt.LoadKeyPEM(test.Input)
+		if errors.Is(err, test.ExpectedError) && (test.ExpectedOutput == nil || keysEqual(test.ExpectedOutput, res)) {
+			t.Logf("Passed %d/%d tests!", i+1, len(tests))
+		} else {
+			t.Errorf("Output and expected output did not match: %s\nFound:\t\t%T %v\nExpected:\t%T %v",
+				test.Input, res, err, test.ExpectedOutput, test.ExpectedError,
+			)
+		}
+	}
+}
+
+func TestEncodeKeyPEM(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Errorf("Failed test because of error: %s", err.Error())
+		t.FailNow()
+	}
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Errorf("Failed test because of error: %s", err.Error())
+		t.FailNow()
+	}
+	edPublicKey, edPrivateKey, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Errorf("Failed test because of error: %s", err.Error())
+		t.FailNow()
+	}
+	tests := []struct {
+		Input         interface{}
+		Expected      interface{}
+		ExpectedError error
+	}{
+		{
+			Input:    rsaKey,
+			Expected: rsaKey,
+		},
+		{
+			Input:    &rsaKey.PublicKey,
+			Expected: &rsaKey.PublicKey,
+		},
+		{
+			Input:    &gojwt.JWK{Key: ecKey},
+			Expected: ecKey,
+		},
+		{
+			Input:    gojwt.BindKey(&ecKey.PublicKey, gojwt.AlgES256),
+			Expected: &ecKey.PublicKey,
+		},
+		{
+			Input:    edPrivateKey,
+			Expected: edPrivateKey,
+		},
+		{
+			Input:    edPublicKey,
+			Expected: edPublicKey,
+		},
+		{
+			Input:         "secret",
+			ExpectedError: gojwt.ErrInvKeyTyp,
+		},
+	}
+	for i, test := range tests {
+		data, err := gojwt.EncodeKeyPEM(test.Input)
+		var res interface{}
+		if err == nil {
+			res, err = gojwt.LoadKeyPEM(data)
+		}
+		var der []byte
+		if err == nil {
+			der, err = gojwt.EncodeKeyDER(test.Input)
+		}
+		var resDER interface{}
+		if err == nil {
+			resDER, err = gojwt.LoadKeyDER(der)
+		}
+		if errors.Is(err, test.ExpectedError) && (test.Expected == nil || keysEqual(test.Expected, res) && keysEqual(test.Expected, resDER)) {
+			t.Logf("Passed %d/%d tests!", i+1, len(tests))
+		} else {
+			t.Errorf("Output and expected output did not match: %T\nFound:\t\t%T %v\nExpected:\t%T %v",
+				test.Input, res, err, test.Expected, test.ExpectedError,
+			)
+		}
+	}
+}
@@ -0,0 +1,385 @@
+package gojwt
+
+import (
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/hmac"
+	"crypto/rsa"
+	"sort"
+	"sync"
+)
+
+// Signer creates signatures for a single algorithm using a key, which is bound to the Signer.
+type Signer interface {
+
+	// Algorithm returns the identification of the algorithm, like it is used in the JWT Header.
+	Algorithm() string
+
+	// Sign signs a message and returns the base64 rawURLEncoded signature.
+	Sign(message string) (signature string, err error)
+}
+
+// Verifier verifies signatures for a single algorithm using a key, which is bound to the Verifier.
+type Verifier interface {
+
+	// Algorithm returns the identification of the algorithm, like it is used in the JWT Header.
+	Algorithm() string
+
+	// Verify verifies a base64 rawURLEncoded signature of a message.
+	// Returns ErrInvSecKey if the signature does not match the key.
+	Verify(message, signature string) (err error)
+}
+
+// SignerFactory creates a Signer from a key.
+// It returns ErrInvKeyTyp if the key can not be used with the algorithm.
+type SignerFactory func(key interface{}) (Signer, error)
+
+// VerifierFactory creates a Verifier from a key.
+// It returns ErrInvKeyTyp if the key can not be used with the algorithm.
+type VerifierFactory func(key interface{}) (Verifier, error)
+
+type signer struct {
+	algorithm string
+	sign      func(message string) (string, error)
+}
+
+func (this *signer) Algorithm() string {
+	return this.algorithm
+}
+
+func (this *signer) Sign(message string) (string, error) {
+	return this.sign(message)
+}
+
+type verifier struct {
+	algorithm string
+	verify    func(message, signature string) error
+}
+
+func (this *verifier) Algorithm() string {
+	return this.algorithm
+}
+
+func (this *verifier) Verify(message, signature string) error {
+	return this.verify(message, signature)
+}
+
+// NewSigner creates a Signer for an algorithm from a signing function.
+func NewSigner(alg string, sign func(message string) (string, error)) Signer {
+	return &signer{
+		algorithm: alg,
+		sign:      sign,
+	}
+}
+
+// NewVerifier creates a Verifier for an algorithm from a verification function.
+func NewVerifier(alg string, verify func(message, signature string) error) Verifier {
+	return &verifier{
+		algorithm: alg,
+		verify:    verify,
+	}
+}
+
+// BoundKey binds a key to the algorithms it may be used with.
+// It can be passed to all functions and methods accepting a key, which then return ErrAlgNotAll
+// before any cryptographic operation, if the algorithm of a token is not one of the bound algorithms.
+type BoundKey struct {
+
+	// Key is the key, which is passed to the SignerFactory or VerifierFactory.
+	Key interface{}
+
+	// Algorithms holds the identifications of the algorithms the key may be used with.
+	Algorithms []string
+}
+
+// BindKey binds a key to the algorithms it may be used with.
+func BindKey(key interface{}, algs ...string) *BoundKey {
+	return &BoundKey{
+		Key:        key,
+		Algorithms: algs,
+	}
+}
+
+// Allows returns a bool, whether the key may be used with an algorithm.
+func (this *BoundKey) Allows(alg string) bool {
+	return contains(this.Algorithms, alg)
+}
+
+// Registry holds the SignerFactory and VerifierFactory values for signature algorithms,
+// identified by the algorithm in the JWT Header. It is safe for concurrent use.
+// The zero value is an empty Registry.
+type Registry struct {
+	mutex     sync.RWMutex
+	signers   map[string]SignerFactory
+	verifiers map[string]VerifierFactory
+}
+
+// DefaultRegistry is the Registry used by the signing and validating methods of JWT,
+// which do not accept a Signer or Verifier directly.
+var DefaultRegistry = NewRegistry()
+
+// NewRegistry creates a new Registry containing all algorithms implemented by this package.
+func NewRegistry() *Registry {
+	registry := &Registry{}
+	registry.registerHS(AlgHS256, SignHS256)
+	registry.registerHS(AlgHS384, SignHS384)
+	registry.registerHS(AlgHS512, SignHS512)
+	registry.registerRSA(AlgRS256, SignRS256, VerifyRS256)
+	registry.registerRSA(AlgRS384, SignRS384, VerifyRS384)
+	registry.registerRSA(AlgRS512, SignRS512, VerifyRS512)
+	registry.registerRSA(AlgPS256, SignPS256, VerifyPS256)
+	registry.registerRSA(AlgPS384, SignPS384, VerifyPS384)
+	registry.registerRSA(AlgPS512, SignPS512, VerifyPS512)
+	registry.registerECDSA(AlgES256, SignES256, VerifyES256)
+	registry.registerECDSA(AlgES384, SignES384, VerifyES384)
+	registry.registerECDSA(AlgES512, SignES512, VerifyES512)
+	registry.registerEd25519(AlgEdDSA, SignEdDSA, VerifyEdDSA)
+	return registry
+}
+
+// Register adds an algorithm to the Registry or replaces an existing one.
+// One of the factories may be nil, if the algorithm only supports signing or verifying.
+func (this *Registry) Register(alg string, signer SignerFactory, verifier VerifierFactory) {
+	this.mutex.Lock()
+	defer this.mutex.Unlock()
+	if this.signers == nil {
+		this.signers = make(map[string]SignerFactory)
+		this.verifiers = make(map[string]VerifierFactory)
+	}
+	delete(this.signers, alg)
+	delete(this.verifiers, alg)
+	if signer != nil {
+		this.signers[alg] = signer
+	}
+	if verifier != nil {
+		this.verifiers[alg] = verifier
+	}
+}
+
+// Unregister removes an algorithm from the Registry.
+func (this *Registry) Unregister(alg string) {
+	this.mutex.Lock()
+	defer this.mutex.Unlock()
+	delete(this.signers, alg)
+	delete(this.verifiers, alg)
+}
+
+// Algorithms returns the sorted identifications of all algorithms in the Registry.
+func (this *Registry) Algorithms() []string {
+	this.mutex.RLock()
+	defer this.mutex.RUnlock()
+	exists := make(map[string]bool)
+	for alg := range this.signers {
+		exists[alg] = true
+	}
+	for alg := range this.verifiers {
+		exists[alg] = true
+	}
+	algorithms := make([]string, 0, len(exists))
+	for alg := range exists {
+		algorithms = append(algorithms, alg)
+	}
+	sort.Strings(algorithms)
+	return algorithms
+}
+
+// Signer creates a Signer for an algorithm and a key.
+// Returns ErrAlgNotAll if the key is a BoundKey, which is not bound to the algorithm, or a JWK with another alg member,
+// ErrInvKeyUse if the key is a JWK, which may not be used for signing, ErrAlgNotImp if the algorithm is not in the Registry or ErrInvKeyTyp if the key is a nil pointer or can not be used with the algorithm.
+func (this *Registry) Signer(alg string, key interface{}) (Signer, error) {
+	key, err := resolveKey(alg, "sign", key)
+	if err != nil {
+		return nil, err
+	}
+	this.mutex.RLock()
+	factory, exists := this.signers[alg]
+	this.mutex.RUnlock()
+	if !exists {
+		return nil, ErrAlgNotImp
+	}
+	return factory(key)
+}
+
+// Verifier creates a Verifier for an algorithm and a key.
+// Returns ErrAlgNotAll if the key is a BoundKey, which is not bound to the algorithm, or a JWK with another alg member,
+// ErrInvKeyUse if the key is a JWK, which may not be used for verifying, ErrAlgNotImp if the algorithm is not in the Registry or ErrInvKeyTyp if the key is a nil pointer or can not be used with the algorithm.
+func (this *Registry) Verifier(alg string, key interface{}) (Verifier, error) {
+	key, err := resolveKey(alg, "verify", key)
+	if err != nil {
+		return nil, err
+	}
+	this.mutex.RLock()
+	factory, exists := this.verifiers[alg]
+	this.mutex.RUnlock()
+	if !exists {
+		return nil, ErrAlgNotImp
+	}
+	return factory(key)
+}
+
+func resolveKey(alg, operation string, key interface{}) (interface{}, error) {
+	if isNilKey(key) {
+		return nil, ErrInvKeyTyp
+	}
+	switch k := key.(type) {
+	case *BoundKey:
+		if !k.Allows(alg) {
+			return nil, ErrAlgNotAll
+		}
+		return resolveKey(alg, operation, k.Key)
+	case *JWK:
+		if k.Algorithm != "" && k.Algorithm != alg {
+			return nil, ErrAlgNotAll
+		}
+		if !k.Allows(alg, operation) {
+			return nil, ErrInvKeyUse
+		}
+		return resolveKey(alg, operation, k.Key)
+	}
+	return key, nil
+}
+
+// isNilKey returns a bool, whether a key is a nil pointer to a key type of this package or the standard library,
+// which would otherwise be dereferenced while signing, verifying or encrypting. Untyped nil keys are passed on,
+// as custom algorithms may not require a key.
+func isNilKey(key interface{}) bool {
+	switch k := key.(type) {
+	case *BoundKey:
+		return k == nil
+	case *JWK:
+		return k == nil
+	case *JWKSet:
+		return k == nil
+	case *RemoteJWKSet:
+		return k == nil
+	case *rsa.PrivateKey:
+		return k == nil
+	case *rsa.PublicKey:
+		return k == nil
+	case *ecdsa.PrivateKey:
+		return k == nil
+	case *ecdsa.PublicKey:
+		return k == nil
+	}
+	return false
+}
+
+func (this *Registry) registerHS(alg string, sign func(string, string) (string, error)) {
+	secretOf := func(key interface{}) (string, error) {
+		switch secret := key.(type) {
+		case string:
+			return secret, nil
+		case []byte:
+			return string(secret), nil
+		}
+		return "", ErrInvKeyTyp
+	}
+	this.Register(alg, func(key interface{}) (Signer, error) {
+		secret, err := secretOf(key)
+		if err != nil {
+			return nil, err
+		}
+		return NewSigner(alg, func(message string) (string, error) {
+			return sign(message, secret)
+		}), nil
+	}, func(key interface{}) (Verifier, error) {
+		secret, err := secretOf(key)
+		if err != nil {
+			return nil, err
+		}
+		return NewVerifier(alg, func(message, signature string) error {
+			expected, err := sign(message, secret)
+			if err != nil {
+				return err
+			}
+			if !hmac.Equal([]byte(expected), []byte(signature)) {
+				return ErrInvSecKey
+			}
+			return nil
+		}), nil
+	})
+}
+
+func (this *Registry) registerRSA(alg string, sign func(string, *rsa.PrivateKey) (string, error), verify func(string, string, *rsa.PublicKey) error) {
+	this.Register(alg, func(key interface{}) (Signer, error) {
+		privateKey, ok := key.(*rsa.PrivateKey)
+		if !ok || privateKey == nil {
+			return nil, ErrInvKeyTyp
+		}
+		return NewSigner(alg, func(message string) (string, error) {
+			return sign(message, privateKey)
+		}), nil
+	}, func(key interface{}) (Verifier, error) {
+		var publicKey *rsa.PublicKey
+		switch k := key.(type) {
+		case *rsa.PublicKey:
+			publicKey = k
+		case *rsa.PrivateKey:
+			if k != nil {
+				publicKey = &k.PublicKey
+			}
+		}
+		if publicKey == nil {
+			return nil, ErrInvKeyTyp
+		}
+		return NewVerifier(alg, func(message, signature string) error {
+			return verify(message, signature, publicKey)
+		}), nil
+	})
+}
+
+func (this *Registry) registerECDSA(alg string, sign func(string, *ecdsa.PrivateKey) (string, error), verify func(string, string, *ecdsa.PublicKey) error) {
+	this.Register(alg, func(key interface{}) (Signer, error) {
+		privateKey, ok := key.(*ecdsa.PrivateKey)
+		if !ok || privateKey == nil {
+			return nil, ErrInvKeyTyp
+		}
+		return NewSigner(alg, func(message string) (string, error) {
+			return sign(message, privateKey)
+		}), nil
+	}, func(key interface{}) (Verifier, error) {
+		var publicKey *ecdsa.PublicKey
+		switch k := key.(type) {
+		case *ecdsa.PublicKey:
+			publicKey = k
+		case *ecdsa.PrivateKey:
+			if k != nil {
+				publicKey = &k.PublicKey
+			}
+		}
+		if publicKey == nil {
+			return nil, ErrInvKeyTyp
+		}
+		return NewVerifier(alg, func(message, signature string) error {
+			return verify(message, signature, publicKey)
+		}), nil
+	})
+}
+
+func (this *Registry) registerEd25519(alg string, sign func(string, ed25519.PrivateKey) (string, error), verify func(string, string, ed25519.PublicKey) error) {
+	this.Register(alg, func(key interface{}) (Signer, error) {
+		privateKey, ok := key.(ed25519.PrivateKey)
+		if !ok {
+			return nil, ErrInvKeyTyp
+		}
+		return NewSigner(alg, func(message string) (string, error) {
+			return sign(message, privateKey)
+		}), nil
+	}, func(key interface{}) (Verifier, error) {
+		var publicKey ed25519.PublicKey
+		switch k := key.(type) {
+		case ed25519.PublicKey:
+			publicKey = k
+		case ed25519.PrivateKey:
+			if len(k) != ed25519.PrivateKeySize {
+				return nil, ErrInvKeyTyp
+			}
+			publicKey, _ = k.Public().(ed25519.PublicKey)
+		default:
+			return nil, ErrInvKeyTyp
+		}
+		return NewVerifier(alg, func(message, signature string) error {
+			return verify(message, signature, publicKey)
+		}), nil
+	})
+}
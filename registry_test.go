@@ -0,0 +1,287 @@
+package gojwt_test
+
+import (
+	"crypto/ecdsa"
+	"crypto/rand"
+	"crypto/rsa"
+	"errors"
+	"github.com/tobyguelly/gojwt"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func newReverseRegistry() *gojwt.Registry {
+	reverse := func(message string) string {
+		runes := []rune(message)
+		for i, j := 0, len(runes)-1; i < j; i, j = i+1, j-1 {
+			runes[i], runes[j] = runes[j], runes[i]
+		}
+		return string(runes)
+	}
+	registry := &gojwt.Registry{}
+	registry.Register("REV", func(key interface{}) (gojwt.Signer, error) {
+		return gojwt.NewSigner("REV", func(message string) (string, error) {
+			return gojwt.EncodeBase64(reverse(message)), nil
+		}), nil
+	}, func(key interface{}) (gojwt.Verifier, error) {
+		return gojwt.NewVerifier("REV", func(message, signature string) error {
+			if gojwt.EncodeBase64(reverse(message)) != signature {
+				return gojwt.ErrInvSecKey
+			}
+			return nil
+		}), nil
+	})
+	return registry
+}
+
+func TestRegistry_Algorithms(t *testing.T) {
+	tests := []struct {
+		Input          *gojwt.Registry
+		ExpectedOutput string
+	}{
+		{
+			Input:          gojwt.NewRegistry(),
+			ExpectedOutput: "ES256,ES384,ES512,EdDSA,HS256,HS384,HS512,PS256,PS384,PS512,RS256,RS384,RS512",
+		},
+		{
+			Input:          &gojwt.Registry{},
+			ExpectedOutput: "",
+		},
+		{
+			Input:          newReverseRegistry(),
+			ExpectedOutput: "REV",
+		},
+	}
+	for i, test := range tests {
+		res := strings.Join(test.Input.Algorithms(), ",")
+		if res == test.ExpectedOutput {
+			t.Logf("Passed %d/%d tests!", i+1, len(tests))
+		} else {
+			t.Errorf("Output and expected output did not match:\nFound:\t\t%s\nExpected:\t%s",
+				res, test.ExpectedOutput,
+			)
+		}
+	}
+}
+
+func TestRegistry_SignerAndVerifier(t *testing.T) {
+	privateKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Errorf("Failed test because of error: %s", err.Error())
+		t.FailNow()
+	}
+	tests := []struct {
+		Registry      *gojwt.Registry
+		Algorithm     string
+		SigningKey    interface{}
+		VerifyingKey  interface{}
+		ExpectedError error
+	}{
+		{
+			Registry:      gojwt.NewRegistry(),
+			Algorithm:     gojwt.AlgHS256,
+			SigningKey:    "secret",
+			VerifyingKey:  []byte("secret"),
+			ExpectedError: nil,
+		},
+		{
+			Registry:      gojwt.NewRegistry(),
+			Algorithm:     gojwt.AlgHS512,
+			SigningKey:    "secret",
+			VerifyingKey:  "wrong",
+			ExpectedError: gojwt.ErrInvSecKey,
+		},
+		{
+			Registry:      gojwt.NewRegistry(),
+			Algorithm:     gojwt.AlgRS256,
+			SigningKey:    privateKey,
+			VerifyingKey:  privateKey,
+			ExpectedError: nil,
+		},
+		{
+			Registry:      gojwt.NewRegistry(),
+			Algorithm:     gojwt.AlgHS256,
+			SigningKey:    privateKey,
+			VerifyingKey:  &privateKey.PublicKey,
+			ExpectedError: gojwt.ErrInvKeyTyp,
+		},
+		{
+			Registry:      gojwt.NewRegistry(),
+			Algorithm:     "REV",
+			SigningKey:    nil,
+			VerifyingKey:  nil,
+			ExpectedError: gojwt.ErrAlgNotImp,
+		},
+		{
+			Registry:      newReverseRegistry(),
+			Algorithm:     "REV",
+			SigningKey:    nil,
+			VerifyingKey:  nil,
+			ExpectedError: nil,
+		},
+		{
+			Registry:      newReverseRegistry(),
+			Algorithm:     gojwt.AlgHS256,
+			SigningKey:    "secret",
+			VerifyingKey:  "secret",
+			ExpectedError: gojwt.ErrAlgNotImp,
+		},
+	}
+	for i, test := range tests {
+		signer, err := test.Registry.Signer(test.Algorithm, test.SigningKey)
+		if err == nil {
+			var verifier gojwt.Verifier
+			verifier, err = test.Registry.Verifier(test.Algorithm, test.VerifyingKey)
+			if err == nil {
+				jwt := gojwt.NewJWT()
+				err = jwt.SignWith(signer)
+				if err == nil {
+					err = jwt.ValidateWith(verifier)
+				}
+			}
+		}
+		if errors.Is(err, test.ExpectedError) {
+			t.Logf("Passed %d/%d tests!", i+1, len(tests))
+		} else {
+			t.Errorf("Output and expected output did not match: %s\nFound:\t\t%v\nExpected:\t%v",
+				test.Algorithm, err, test.ExpectedError,
+			)
+		}
+	}
+}
+
+func TestRegistry_Concurrency(t *testing.T) {
+	registry := gojwt.NewRegistry()
+	var wg sync.WaitGroup
+	for i := 0; i < 16; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			registry.Register("REV", nil, nil)
+			registry.Unregister("REV")
+		}()
+		go func() {
+			defer wg.Done()
+			signer, err := registry.Signer(gojwt.AlgHS256, "secret")
+			if err != nil {
+				t.Errorf("Failed test because of error: %s", err.Error())
+				return
+			}
+			jwt := gojwt.NewJWT()
+			err = jwt.SignWith(signer)
+			if err != nil {
+				t.Errorf("Failed test because of error: %s", err.Error())
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+func TestBindKey(t *testing.T) {
+	privateKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Errorf("Failed test because of error: %s", err.Error())
+		t.FailNow()
+	}
+	rsaToken := gojwt.NewJWT()
+	rsaToken.Header.Algorithm = gojwt.AlgRS256
+	err = rsaToken.SignWithKey(privateKey)
+	if err != nil {
+		t.Errorf("Failed test because of error: %s", err.Error())
+		t.FailNow()
+	}
+	confusedToken := gojwt.NewJWT()
+	err = confusedToken.Sign(privateKey.PublicKey.N.String())
+	if err != nil {
+		t.Errorf("Failed test because of error: %s", err.Error())
+		t.FailNow()
+	}
+	tests := []struct {
+		Input         gojwt.JWT
+		Key           interface{}
+		ExpectedError error
+	}{
+		{
+			Input:         rsaToken,
+			Key:           gojwt.BindKey(&privateKey.PublicKey, gojwt.AlgRS256),
+			ExpectedError: nil,
+		},
+		{
+			Input:         rsaToken,
+			Key:           gojwt.BindKey(&privateKey.PublicKey, gojwt.AlgPS256, gojwt.AlgRS512),
+			ExpectedError: gojwt.ErrAlgNotAll,
+		},
+		{
+			Input:         confusedToken,
+			Key:           gojwt.BindKey(privateKey.PublicKey.N.String(), gojwt.AlgRS256),
+			ExpectedError: gojwt.ErrAlgNotAll,
+		},
+		{
+			Input:         confusedToken,
+			Key:           gojwt.BindKey(gojwt.BindKey(privateKey.PublicKey.N.String(), gojwt.AlgHS256), gojwt.AlgRS256),
+			ExpectedError: gojwt.ErrAlgNotAll,
+		},
+	}
+	for i, test := range tests {
+		err := test.Input.ValidateWithKey(test.Key)
+		if errors.Is(err, test.ExpectedError) {
+			t.Logf("Passed %d/%d tests!", i+1, len(tests))
+		} else {
+			t.Errorf("Output and expected output did not match: %s\nFound:\t\t%v\nExpected:\t%v",
+				test.Input.Header.Algorithm, err, test.ExpectedError,
+			)
+		}
+	}
+}
+
+func TestBindKey_NoVerification(t *testing.T) {
+	registry := &gojwt.Registry{}
+	registry.Register(gojwt.AlgHS256, nil, func(key interface{}) (gojwt.Verifier, error) {
+		t.Errorf("Failed test because the verifier was created for a disallowed algorithm")
+		return nil, gojwt.ErrInvKeyTyp
+	})
+	_, err := registry.Verifier(gojwt.AlgHS256, gojwt.BindKey("secret", gojwt.AlgRS256))
+	if err == gojwt.ErrAlgNotAll {
+		t.Logf("Passed %d/%d tests!", 1, 1)
+	} else {
+		t.Errorf("Output and expected output did not match:\nFound:\t\t%v\nExpected:\t%v",
+			err, gojwt.ErrAlgNotAll,
+		)
+	}
+}
+
+func TestRegistry_NilKeys(t *testing.T) {
+	tests := []struct {
+		Input     interface{}
+		Algorithm string
+	}{
+		{Input: nil, Algorithm: gojwt.AlgHS256},
+		{Input: (*gojwt.JWK)(nil), Algorithm: gojwt.AlgRS256},
+		{Input: (*gojwt.BoundKey)(nil), Algorithm: gojwt.AlgRS256},
+		{Input: (*gojwt.JWKSet)(nil), Algorithm: gojwt.AlgRS256},
+		{Input: (*gojwt.RemoteJWKSet)(nil), Algorithm: gojwt.AlgRS256},
+		{Input: (*rsa.PrivateKey)(nil), Algorithm: gojwt.AlgRS256},
+		{Input: (*rsa.PublicKey)(nil), Algorithm: gojwt.AlgPS256},
+		{Input: (*ecdsa.PrivateKey)(nil), Algorithm: gojwt.AlgES256},
+		{Input: (*ecdsa.PublicKey)(nil), Algorithm: gojwt.AlgES256},
+		{Input: gojwt.BindKey((*rsa.PrivateKey)(nil), gojwt.AlgRS256), Algorithm: gojwt.AlgRS256},
+		{Input: &gojwt.JWK{}, Algorithm: gojwt.AlgRS256},
+	}
+	for i, test := range tests {
+		jwt := gojwt.NewJWT()
+		jwt.Header.Algorithm = test.Algorithm
+		signErr := jwt.SignWithKey(test.Input)
+		jwt.Signature = "c2lnbmF0dXJl"
+		validateErr := jwt.ValidateWithKey(test.Input)
+		validatorErr := gojwt.NewValidator().Validate(&jwt, test.Input)
+		if errors.Is(signErr, gojwt.ErrInvKeyTyp) && errors.Is(validateErr, gojwt.ErrInvKeyTyp) &&
+			errors.Is(validatorErr, gojwt.ErrInvKeyTyp) {
+			t.Logf("Passed %d/%d tests!", i+1, len(tests))
+		} else {
+			t.Errorf("Output and expected output did not match: %T\nFound:\t\t%v %v %v\nExpected:\t%v",
+				test.Input, signErr, validateErr, validatorErr, gojwt.ErrInvKeyTyp,
+			)
+		}
+	}
+}
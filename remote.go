@@ -0,0 +1,246 @@
+package gojwt
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// DefaultCacheDuration is the duration a RemoteJWKSet caches a key set, if the response has no Cache-Control max-age.
+	DefaultCacheDuration = time.Hour
+
+	// DefaultRefetchInterval is the minimum interval between two requests of a RemoteJWKSet.
+	DefaultRefetchInterval = time.Minute
+
+	maxKeySetSize = 1 << 20
+)
+
+// RemoteOption configures a RemoteJWKSet.
+type RemoteOption func(*RemoteJWKSet)
+
+// UseHTTPClient sets the http.Client used to fetch the key set, which defaults to http.DefaultClient.
+func UseHTTPClient(client *http.Client) RemoteOption {
+	return func(remote *RemoteJWKSet) {
+		remote.client = client
+	}
+}
+
+// CacheDuration sets the duration the key set is cached, if the response has no Cache-Control max-age.
+func CacheDuration(duration time.Duration) RemoteOption {
+	return func(remote *RemoteJWKSet) {
+		remote.cacheDuration = duration
+	}
+}
+
+// RefetchInterval sets the minimum interval between two requests, which limits the requests
+// caused by tokens with unknown kid values or by a failing endpoint.
+func RefetchInterval(interval time.Duration) RemoteOption {
+	return func(remote *RemoteJWKSet) {
+		remote.refetchInterval = interval
+	}
+}
+
+// UseRemoteClock sets the function returning the current time, which decides when the cached key set expires
+// and defaults to time.Now.
+func UseRemoteClock(now func() time.Time) RemoteOption {
+	return func(remote *RemoteJWKSet) {
+		remote.now = now
+	}
+}
+
+// RemoteJWKSet is a KeyProvider, which fetches a JWKSet over HTTP, like from the jwks_uri of an OpenID provider.
+// The key set is cached as long as the Cache-Control max-age of the response allows and fetched again
+// when it has expired or a JWT references an unknown kid, but never more often than the refetch interval.
+// If fetching fails, the last key set is used until it can be fetched again. It is safe for concurrent use.
+type RemoteJWKSet struct {
+	url             string
+	client          *http.Client
+	cacheDuration   time.Duration
+	refetchInterval time.Duration
+	now             func() time.Time
+
+	mutex   sync.RWMutex
+	set     *JWKSet
+	err     error
+	expires time.Time
+	fetched time.Time
+
+	fetchMutex sync.Mutex
+	startOnce  sync.Once
+	closeOnce  sync.Once
+	done       chan struct{}
+}
+
+// NewRemoteJWKSet creates a RemoteJWKSet for a URL. The key set is fetched on first use,
+// or in the background after calling Start.
+func NewRemoteJWKSet(url string, opts ...RemoteOption) *RemoteJWKSet {
+	remote := &RemoteJWKSet{
+		url:             url,
+		client:          http.DefaultClient,
+		cacheDuration:   DefaultCacheDuration,
+		refetchInterval: DefaultRefetchInterval,
+		now:             time.Now,
+		done:            make(chan struct{}),
+	}
+	for _, opt := range opts {
+		opt(remote)
+	}
+	return remote
+}
+
+// KeysFor is the implementation of the KeyProvider interface.
+// The key set is fetched again if it has expired or the kid in the Header is unknown.
+// Returns an error wrapping ErrKeySetFtch if no key set could be fetched yet
+// and the same errors as the KeysFor method of JWKSet otherwise.
+func (this *RemoteJWKSet) KeysFor(header Header) ([]*JWK, error) {
+	set, err := this.keySet(false)
+	if err != nil {
+		return nil, err
+	}
+	keys, err := set.KeysFor(header)
+	if errors.Is(err, ErrKeyNotFnd) && header.KeyID != "" {
+		refreshed, _ := this.keySet(true)
+		if refreshed != nil && refreshed != set {
+			return refreshed.KeysFor(header)
+		}
+	}
+	return keys, err
+}
+
+// KeySet returns the cached JWKSet and fetches it, if it has expired.
+// Returns an error wrapping ErrKeySetFtch if no key set could be fetched yet.
+func (this *RemoteJWKSet) KeySet() (*JWKSet, error) {
+	return this.keySet(false)
+}
+
+// Refresh fetches the key set immediately, regardless of the cache and the refetch interval.
+// Returns an error wrapping ErrKeySetFtch if the key set could not be fetched, the last key set is kept then.
+func (this *RemoteJWKSet) Refresh() error {
+	this.fetchMutex.Lock()
+	defer this.fetchMutex.Unlock()
+	return this.fetch()
+}
+
+// Start starts refreshing the key set in the background, whenever it expires.
+// The refreshing is stopped by Close.
+func (this *RemoteJWKSet) Start() {
+	this.startOnce.Do(func() {
+		go this.run()
+	})
+}
+
+// Close stops refreshing the key set in the background.
+func (this *RemoteJWKSet) Close() {
+	this.closeOnce.Do(func() {
+		close(this.done)
+	})
+}
+
+func (this *RemoteJWKSet) run() {
+	for {
+		this.mutex.RLock()
+		now := this.now()
+		wait := this.expires.Sub(now)
+		if next := this.fetched.Add(this.refetchInterval).Sub(now); next > wait {
+			wait = next
+		}
+		if !this.fetched.IsZero() && wait < time.Second {
+			wait = time.Second
+		}
+		this.mutex.RUnlock()
+		timer := time.NewTimer(wait)
+		select {
+		case <-this.done:
+			timer.Stop()
+			return
+		case <-timer.C:
+			_, _ = this.keySet(true)
+		}
+	}
+}
+
+func (this *RemoteJWKSet) keySet(force bool) (*JWKSet, error) {
+	this.mutex.RLock()
+	set, expires := this.set, this.expires
+	this.mutex.RUnlock()
+	if set != nil && !force && this.now().Before(expires) {
+		return set, nil
+	}
+	this.fetchMutex.Lock()
+	defer this.fetchMutex.Unlock()
+	this.mutex.RLock()
+	current, fetched, err := this.set, this.fetched, this.err
+	this.mutex.RUnlock()
+	if current != set || this.now().Sub(fetched) < this.refetchInterval {
+		if current == nil {
+			return nil, err
+		}
+		return current, nil
+	}
+	err = this.fetch()
+	this.mutex.RLock()
+	defer this.mutex.RUnlock()
+	if this.set == nil {
+		return nil, err
+	}
+	return this.set, nil
+}
+
+func (this *RemoteJWKSet) fetch() error {
+	set, maxAge, err := this.request()
+	this.mutex.Lock()
+	defer this.mutex.Unlock()
+	now := this.now()
+	this.fetched = now
+	if err != nil {
+		this.err = err
+		return err
+	}
+	this.set, this.err = set, nil
+	this.expires = now.Add(maxAge)
+	return nil
+}
+
+func (this *RemoteJWKSet) request() (*JWKSet, time.Duration, error) {
+	response, err := this.client.Get(this.url)
+	if err != nil {
+		return nil, 0, fmt.Errorf("%w: %s", ErrKeySetFtch, err.Error())
+	}
+	defer response.Body.Close()
+	if response.StatusCode != http.StatusOK {
+		return nil, 0, fmt.Errorf("%w: unexpected status %s", ErrKeySetFtch, response.Status)
+	}
+	data, err := io.ReadAll(io.LimitReader(response.Body, maxKeySetSize))
+	if err != nil {
+		return nil, 0, fmt.Errorf("%w: %s", ErrKeySetFtch, err.Error())
+	}
+	set, err := LoadJWKSet(data)
+	if err != nil {
+		return nil, 0, fmt.Errorf("%w: %s", ErrKeySetFtch, err.Error())
+	}
+	return set, this.maxAge(response.Header.Get("Cache-Control")), nil
+}
+
+func (this *RemoteJWKSet) maxAge(cacheControl string) time.Duration {
+	maxAge := this.cacheDuration
+	for _, directive := range strings.Split(cacheControl, ",") {
+		directive = strings.ToLower(strings.TrimSpace(directive))
+		switch {
+		case directive == "no-store" || directive == "no-cache":
+			return 0
+		case strings.HasPrefix(directive, "max-age="):
+			seconds, err := strconv.ParseInt(strings.TrimPrefix(directive, "max-age="), 10, 64)
+			if err == nil && seconds >= 0 && seconds <= int64(math.MaxInt64/time.Second) {
+				maxAge = time.Duration(seconds) * time.Second
+			}
+		}
+	}
+	return maxAge
+}
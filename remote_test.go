@@ -0,0 +1,235 @@
+package gojwt_test
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"github.com/tobyguelly/gojwt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+type keySetResponse struct {
+	Status       int
+	CacheControl string
+	Keys         []string
+}
+
+func newKeySetServer(t *testing.T, keys map[string]*ecdsa.PrivateKey, responses []keySetResponse) (*httptest.Server, *int32) {
+	var requests int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		i := int(atomic.AddInt32(&requests, 1)) - 1
+		if i >= len(responses) {
+			i = len(responses) - 1
+		}
+		response := responses[i]
+		if response.Status != http.StatusOK {
+			w.WriteHeader(response.Status)
+			return
+		}
+		set := &gojwt.JWKSet{Keys: []*gojwt.JWK{}}
+		for _, kid := range response.Keys {
+			set.Keys = append(set.Keys, &gojwt.JWK{Key: &keys[kid].PublicKey, KeyID: kid})
+		}
+		data, err := json.Marshal(set)
+		if err != nil {
+			t.Errorf("Failed test because of error: %s", err.Error())
+		}
+		w.Header().Set("Cache-Control", response.CacheControl)
+		_, _ = w.Write(data)
+	}))
+	return server, &requests
+}
+
+type notifyingTransport struct {
+	next     http.RoundTripper
+	requests chan struct{}
+}
+
+func (this notifyingTransport) RoundTrip(request *http.Request) (*http.Response, error) {
+	response, err := this.next.RoundTrip(request)
+	select {
+	case this.requests <- struct{}{}:
+	default:
+	}
+	return response, err
+}
+
+func TestRemoteJWKSet_KeysFor(t *testing.T) {
+	keys := map[string]*ecdsa.PrivateKey{}
+	for _, kid := range []string{"a", "b"} {
+		key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		if err != nil {
+			t.Errorf("Failed test because of error: %s", err.Error())
+			t.FailNow()
+		}
+		keys[kid] = key
+	}
+	tests := []struct {
+		Responses        []keySetResponse
+		RefetchInterval  time.Duration
+		KeyIDs           []string
+		ExpectedErrors   []error
+		ExpectedRequests int32
+	}{
+		{
+			Responses: []keySetResponse{
+				{Status: http.StatusOK, CacheControl: "public, max-age=3600", Keys: []string{"a"}},
+			},
+			KeyIDs:           []string{"a", "a", "a"},
+			ExpectedErrors:   []error{nil, nil, nil},
+			ExpectedRequests: 1,
+		},
+		{
+			Responses: []keySetResponse{
+				{Status: http.StatusOK, CacheControl: "no-store", Keys: []string{"a"}},
+			},
+			KeyIDs:           []string{"a", "a", "a"},
+			ExpectedErrors:   []error{nil, nil, nil},
+			ExpectedRequests: 3,
+		},
+		{
+			Responses: []keySetResponse{
+				{Status: http.StatusOK, CacheControl: "max-age=3600", Keys: []string{"a"}},
+				{Status: http.StatusOK, CacheControl: "max-age=3600", Keys: []string{"a", "b"}},
+			},
+			KeyIDs:           []string{"a", "b", "b"},
+			ExpectedErrors:   []error{nil, nil, nil},
+			ExpectedRequests: 2,
+		},
+		{
+			Responses: []keySetResponse{
+				{Status: http.StatusOK, CacheControl: "max-age=3600", Keys: []string{"a"}},
+				{Status: http.StatusOK, CacheControl: "max-age=3600", Keys: []string{"a", "b"}},
+			},
+			RefetchInterval:  time.Hour,
+			KeyIDs:           []string{"a", "b", "b", "b"},
+			ExpectedErrors:   []error{nil, gojwt.ErrKeyNotFnd, gojwt.ErrKeyNotFnd, gojwt.ErrKeyNotFnd},
+			ExpectedRequests: 1,
+		},
+		{
+			Responses: []keySetResponse{
+				{Status: http.StatusOK, CacheControl: "no-cache", Keys: []string{"a"}},
+				{Status: http.StatusInternalServerError},
+			},
+			KeyIDs:           []string{"a", "a", "b"},
+			ExpectedErrors:   []error{nil, nil, gojwt.ErrKeyNotFnd},
+			ExpectedRequests: 4,
+		},
+		{
+			Responses: []keySetResponse{
+				{Status: http.StatusNotFound},
+			},
+			RefetchInterval:  time.Hour,
+			KeyIDs:           []string{"a", "a"},
+			ExpectedErrors:   []error{gojwt.ErrKeySetFtch, gojwt.ErrKeySetFtch},
+			ExpectedRequests: 1,
+		},
+	}
+	for i, test := range tests {
+		server, requests := newKeySetServer(t, keys, test.Responses)
+		remote := gojwt.NewRemoteJWKSet(server.URL,
+			gojwt.UseHTTPClient(server.Client()),
+			gojwt.RefetchInterval(test.RefetchInterval),
+		)
+		passed := true
+		for j, kid := range test.KeyIDs {
+			jwt := gojwt.NewJWT()
+			jwt.Header.Algorithm = gojwt.AlgES256
+			jwt.Header.KeyID = kid
+			err := jwt.SignWithKey(keys[kid])
+			if err == nil {
+				err = jwt.ValidateWithKey(remote)
+			}
+			if !errors.Is(err, test.ExpectedErrors[j]) {
+				passed = false
+				t.Errorf("Output and expected output did not match: %d/%s\nFound:\t\t%v\nExpected:\t%v",
+					i, kid, err, test.ExpectedErrors[j],
+				)
+			}
+		}
+		if res := atomic.LoadInt32(requests); res != test.ExpectedRequests {
+			passed = false
+			t.Errorf("Output and expected output did not match: %d\nFound:\t\t%d requests\nExpected:\t%d requests",
+				i, res, test.ExpectedRequests,
+			)
+		}
+		server.Close()
+		if passed {
+			t.Logf("Passed %d/%d tests!", i+1, len(tests))
+		}
+	}
+}
+
+func TestRemoteJWKSet_Start(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Errorf("Failed test because of error: %s", err.Error())
+		t.FailNow()
+	}
+	server, requests := newKeySetServer(t, map[string]*ecdsa.PrivateKey{"a": key}, []keySetResponse{
+		{Status: http.StatusOK, CacheControl: "max-age=3600", Keys: []string{"a"}},
+	})
+	defer server.Close()
+	client := server.Client()
+	fetched := make(chan struct{}, 1)
+	client.Transport = notifyingTransport{next: client.Transport, requests: fetched}
+	remote := gojwt.NewRemoteJWKSet(server.URL, gojwt.UseHTTPClient(client))
+	remote.Start()
+	defer remote.Close()
+	select {
+	case <-fetched:
+	case <-time.After(5 * time.Second):
+		t.Errorf("Failed test because the key set has not been fetched in the background")
+		t.FailNow()
+	}
+	set, err := remote.KeySet()
+	if err != nil || set.Key("a") == nil || atomic.LoadInt32(requests) != 1 {
+		t.Errorf("Output and expected output did not match:\nFound:\t\t%v, %d requests\nExpected:\t%v, %d requests",
+			err, atomic.LoadInt32(requests), nil, 1,
+		)
+	}
+}
+
+func TestRemoteJWKSet_Clock(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Errorf("Failed test because of error: %s", err.Error())
+		t.FailNow()
+	}
+	server, requests := newKeySetServer(t, map[string]*ecdsa.PrivateKey{"a": key}, []keySetResponse{
+		{Status: http.StatusOK, CacheControl: "max-age=120", Keys: []string{"a"}},
+	})
+	defer server.Close()
+	now := time.Unix(1700000000, 0)
+	remote := gojwt.NewRemoteJWKSet(server.URL,
+		gojwt.UseHTTPClient(server.Client()),
+		gojwt.UseRemoteClock(func() time.Time { return now }),
+	)
+	tests := []struct {
+		Elapsed          time.Duration
+		ExpectedRequests int32
+	}{
+		{Elapsed: 0, ExpectedRequests: 1},
+		{Elapsed: time.Minute, ExpectedRequests: 1},
+		{Elapsed: 2 * time.Minute, ExpectedRequests: 2},
+		{Elapsed: 3 * time.Minute, ExpectedRequests: 2},
+	}
+	start := now
+	for i, test := range tests {
+		now = start.Add(test.Elapsed)
+		set, err := remote.KeySet()
+		if err == nil && set.Key("a") != nil && atomic.LoadInt32(requests) == test.ExpectedRequests {
+			t.Logf("Passed %d/%d tests!", i+1, len(tests))
+		} else {
+			t.Errorf("Output and expected output did not match: %v\nFound:\t\t%v, %d requests\nExpected:\t%v, %d requests",
+				test.Elapsed, err, atomic.LoadInt32(requests), nil, test.ExpectedRequests,
+			)
+		}
+	}
+}
@@ -0,0 +1,192 @@
+package gojwt
+
+import (
+	"container/heap"
+	"sync"
+	"time"
+)
+
+// RevocationStore stores revoked tokens by their jti (JWTID) claim, to reject them before they expire.
+// A revocation may be limited to the tokens of a subject, to allow JWTIDs, which are only unique per subject.
+// Implementations must be safe for concurrent use.
+type RevocationStore interface {
+
+	// Revoke revokes the tokens with a JWTID until an expiry time, after which the revocation may be removed.
+	// If the subject is empty, the tokens are revoked for every subject. A zero expiry time never expires.
+	Revoke(jti, subject string, expiry time.Time) error
+
+	// IsRevoked returns a bool, whether the token with a JWTID and a subject has been revoked
+	// for the subject or for every subject.
+	IsRevoked(jti, subject string) (bool, error)
+}
+
+// RevokeToken revokes a JWT in a RevocationStore by its jti (JWTID) claim for every subject until it expires.
+// Returns ErrMisTokClm if the JWT does not have a jti claim.
+func RevokeToken(store RevocationStore, jwt *JWT) error {
+	if jwt.Payload.JWTID == "" {
+		return ErrMisTokClm
+	}
+	var expiry time.Time
+	if jwt.Payload.ExpirationTime != nil {
+		expiry = jwt.Payload.ExpirationTime.Time
+	}
+	return store.Revoke(jwt.Payload.JWTID, "", expiry)
+}
+
+// CheckRevocation sets a RevocationStore, which is queried for tokens with a jti (JWTID) claim.
+// Revoked tokens are rejected with ErrTokRevk and errors of the RevocationStore are returned as ErrStoreUnav,
+// which also matches the error of the store.
+func CheckRevocation(store RevocationStore) ValidatorOption {
+	return func(validator *Validator) {
+		validator.revocations = store
+	}
+}
+
+// MemoryStoreOption configures a MemoryRevocationStore or a MemoryRefreshStore when it is created.
+type MemoryStoreOption func(store *memoryStore)
+
+// UseStoreClock sets the function returning the current time, which decides when entries expire
+// and defaults to time.Now.
+func UseStoreClock(now func() time.Time) MemoryStoreOption {
+	return func(store *memoryStore) {
+		store.now = now
+	}
+}
+
+// memoryStore holds the configuration shared by the in-memory stores.
+type memoryStore struct {
+	now func() time.Time
+}
+
+func newMemoryStore(options []MemoryStoreOption) memoryStore {
+	store := memoryStore{now: time.Now}
+	for _, option := range options {
+		option(&store)
+	}
+	return store
+}
+
+// MemoryRevocationStore is a RevocationStore, which keeps the revoked tokens in memory
+// and removes them automatically once they have expired.
+type MemoryRevocationStore struct {
+	memoryStore
+	mutex   sync.Mutex
+	entries map[revocationKey]*expiryEntry
+	expiry  expiryQueue
+}
+
+// NewMemoryRevocationStore creates a new and empty MemoryRevocationStore with a list of options.
+func NewMemoryRevocationStore(options ...MemoryStoreOption) *MemoryRevocationStore {
+	return &MemoryRevocationStore{
+		memoryStore: newMemoryStore(options),
+		entries:     make(map[revocationKey]*expiryEntry),
+	}
+}
+
+// Revoke is the implementation of the RevocationStore interface.
+// Revocations with an expiry time in the past are ignored.
+func (this *MemoryRevocationStore) Revoke(jti, subject string, expiry time.Time) error {
+	this.mutex.Lock()
+	defer this.mutex.Unlock()
+	this.evict()
+	if !expiry.IsZero() && !this.now().Before(expiry) {
+		return nil
+	}
+	key := revocationKey{jti: jti, subject: subject}
+	entry, ok := this.entries[key]
+	if !ok {
+		entry = &expiryEntry{key: key, expiry: expiry}
+		this.entries[key] = entry
+		if !expiry.IsZero() {
+			heap.Push(&this.expiry, entry)
+		}
+		return nil
+	}
+	switch {
+	case entry.expiry.IsZero():
+	case expiry.IsZero():
+		heap.Remove(&this.expiry, entry.index)
+		entry.expiry = expiry
+	case expiry.After(entry.expiry):
+		entry.expiry = expiry
+		heap.Fix(&this.expiry, entry.index)
+	}
+	return nil
+}
+
+// IsRevoked is the implementation of the RevocationStore interface.
+func (this *MemoryRevocationStore) IsRevoked(jti, subject string) (bool, error) {
+	this.mutex.Lock()
+	defer this.mutex.Unlock()
+	this.evict()
+	_, revoked := this.entries[revocationKey{jti: jti}]
+	if !revoked && subject != "" {
+		_, revoked = this.entries[revocationKey{jti: jti, subject: subject}]
+	}
+	return revoked, nil
+}
+
+// Len returns the amount of revocations, which have not expired yet.
+func (this *MemoryRevocationStore) Len() int {
+	this.mutex.Lock()
+	defer this.mutex.Unlock()
+	this.evict()
+	return len(this.entries)
+}
+
+func (this *MemoryRevocationStore) evict() {
+	for _, entry := range this.expiry.popExpired(this.now()) {
+		delete(this.entries, entry.key.(revocationKey))
+	}
+}
+
+type revocationKey struct {
+	jti     string
+	subject string
+}
+
+type expiryEntry struct {
+	key    interface{}
+	expiry time.Time
+	index  int
+}
+
+// expiryQueue is a min-heap of entries ordered by their expiry time.
+type expiryQueue []*expiryEntry
+
+func (this expiryQueue) Len() int {
+	return len(this)
+}
+
+func (this expiryQueue) Less(i, j int) bool {
+	return this[i].expiry.Before(this[j].expiry)
+}
+
+func (this expiryQueue) Swap(i, j int) {
+	this[i], this[j] = this[j], this[i]
+	this[i].index = i
+	this[j].index = j
+}
+
+func (this *expiryQueue) Push(x interface{}) {
+	entry := x.(*expiryEntry)
+	entry.index = len(*this)
+	*this = append(*this, entry)
+}
+
+func (this *expiryQueue) Pop() interface{} {
+	old := *this
+	entry := old[len(old)-1]
+	old[len(old)-1] = nil
+	*this = old[:len(old)-1]
+	return entry
+}
+
+// popExpired removes and returns all entries, which have expired at a time.
+func (this *expiryQueue) popExpired(now time.Time) []*expiryEntry {
+	var res []*expiryEntry
+	for len(*this) > 0 && !now.Before((*this)[0].expiry) {
+		res = append(res, heap.Pop(this).(*expiryEntry))
+	}
+	return res
+}
@@ -0,0 +1,149 @@
+package gojwt_test
+
+import (
+	"errors"
+	"github.com/tobyguelly/gojwt"
+	"testing"
+	"time"
+)
+
+var errStoreUnavailable = errors.New("STORE UNAVAILABLE")
+
+type unavailableRevocationStore struct{}
+
+func (this unavailableRevocationStore) Revoke(jti, subject string, expiry time.Time) error {
+	return errStoreUnavailable
+}
+
+func (this unavailableRevocationStore) IsRevoked(jti, subject string) (bool, error) {
+	return false, errStoreUnavailable
+}
+
+func TestMemoryRevocationStore(t *testing.T) {
+	now := time.Now()
+	store := gojwt.NewMemoryRevocationStore()
+	revocations := []struct {
+		JWTID   string
+		Subject string
+		Expiry  time.Time
+	}{
+		{JWTID: "a", Expiry: now.Add(time.Hour)},
+		{JWTID: "b", Subject: "Joe", Expiry: now.Add(time.Hour)},
+		{JWTID: "c"},
+		{JWTID: "d", Expiry: now.Add(-time.Second)},
+		{JWTID: "e", Expiry: now.Add(time.Hour)},
+		{JWTID: "e", Expiry: now.Add(time.Minute)},
+	}
+	for _, revocation := range revocations {
+		err := store.Revoke(revocation.JWTID, revocation.Subject, revocation.Expiry)
+		if err != nil {
+			t.Errorf("Failed test because of error: %s", err.Error())
+			t.FailNow()
+		}
+	}
+	tests := []struct {
+		JWTID    string
+		Subject  string
+		Expected bool
+	}{
+		{JWTID: "a", Subject: "Joe", Expected: true},
+		{JWTID: "a", Expected: true},
+		{JWTID: "b", Subject: "Joe", Expected: true},
+		{JWTID: "b", Subject: "Jane", Expected: false},
+		{JWTID: "b", Expected: false},
+		{JWTID: "c", Subject: "Joe", Expected: true},
+		{JWTID: "d", Subject: "Joe", Expected: false},
+		{JWTID: "e", Subject: "Joe", Expected: true},
+		{JWTID: "f", Subject: "Joe", Expected: false},
+	}
+	for i, test := range tests {
+		revoked, err := store.IsRevoked(test.JWTID, test.Subject)
+		if err == nil && revoked == test.Expected {
+			t.Logf("Passed %d/%d tests!", i+1, len(tests))
+		} else {
+			t.Errorf("Output and expected output did not match: %s %s\nFound:\t\t%v %v\nExpected:\t%v",
+				test.JWTID, test.Subject, revoked, err, test.Expected,
+			)
+		}
+	}
+	if store.Len() != 4 {
+		t.Errorf("Output and expected output did not match: %s\nFound:\t\t%d\nExpected:\t%d", "Len", store.Len(), 4)
+	}
+}
+
+func TestMemoryRevocationStore_Evict(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	store := gojwt.NewMemoryRevocationStore(gojwt.UseStoreClock(func() time.Time { return now }))
+	err := store.Revoke("a", "", now.Add(time.Minute))
+	if err != nil {
+		t.Errorf("Failed test because of error: %s", err.Error())
+		t.FailNow()
+	}
+	err = store.Revoke("b", "", now.Add(time.Hour))
+	if err != nil {
+		t.Errorf("Failed test because of error: %s", err.Error())
+		t.FailNow()
+	}
+	now = now.Add(time.Minute)
+	revoked, err := store.IsRevoked("a", "")
+	if err != nil || revoked || store.Len() != 1 {
+		t.Errorf("Output and expected output did not match: %s\nFound:\t\t%v %d\nExpected:\t%v %d", "a", revoked, store.Len(), false, 1)
+	}
+}
+
+func TestValidator_CheckRevocation(t *testing.T) {
+	store := gojwt.NewMemoryRevocationStore()
+	revoked := gojwt.NewJWT()
+	revoked.Payload.JWTID = "revoked"
+	revoked.Payload.ExpirationTime = gojwt.Wrap(time.Now().Add(time.Hour))
+	err := gojwt.RevokeToken(store, &revoked)
+	if err != nil {
+		t.Errorf("Failed test because of error: %s", err.Error())
+		t.FailNow()
+	}
+	active := gojwt.NewJWT()
+	active.Payload.JWTID = "active"
+	tests := []struct {
+		Input         gojwt.JWT
+		Store         gojwt.RevocationStore
+		ExpectedError error
+	}{
+		{
+			Input:         active,
+			Store:         store,
+			ExpectedError: nil,
+		},
+		{
+			Input:         revoked,
+			Store:         store,
+			ExpectedError: gojwt.ErrTokRevk,
+		},
+		{
+			Input:         gojwt.NewJWT(),
+			Store:         unavailableRevocationStore{},
+			ExpectedError: nil,
+		},
+		{
+			Input:         active,
+			Store:         unavailableRevocationStore{},
+			ExpectedError: errStoreUnavailable,
+		},
+	}
+	for i, test := range tests {
+		token, err := test.Input.SignParse("secret")
+		if err == nil {
+			_, err = gojwt.NewValidator(gojwt.CheckRevocation(test.Store)).Parse(token, "secret")
+		}
+		if errors.Is(err, test.ExpectedError) {
+			t.Logf("Passed %d/%d tests!", i+1, len(tests))
+		} else {
+			t.Errorf("Output and expected output did not match: %s\nFound:\t\t%v\nExpected:\t%v",
+				test.Input.Payload.JWTID, err, test.ExpectedError,
+			)
+		}
+	}
+	err = gojwt.RevokeToken(store, &gojwt.JWT{})
+	if !errors.Is(err, gojwt.ErrMisTokClm) {
+		t.Errorf("Output and expected output did not match: %s\nFound:\t\t%v\nExpected:\t%v", "RevokeToken", err, gojwt.ErrMisTokClm)
+	}
+}
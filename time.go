@@ -1,6 +1,7 @@
 package gojwt
 
 import (
+	"math"
 	"strconv"
 	"time"
 )
@@ -45,11 +46,25 @@ func (this *Time) MarshalText() ([]byte, error) {
 }
 
 // UnmarshalText is the implementation of the encoding.TextUnmarshaler interface.
-// It parses an UNIX-Timestamp into a Time value.
+// It parses an UNIX-Timestamp into a Time value. Timestamps with fractional seconds
+// or in exponent notation are accepted, as they are valid NumericDate values.
+// Returns strconv.ErrRange if the timestamp is not finite or does not fit into int64 seconds.
 func (this *Time) UnmarshalText(data []byte) error {
 	res, err := strconv.ParseInt(string(data), 10, 64)
-	this.Time = time.Unix(res, 0)
-	return err
+	if err == nil {
+		this.Time = time.Unix(res, 0)
+		return nil
+	}
+	seconds, err := strconv.ParseFloat(string(data), 64)
+	if err != nil {
+		return err
+	}
+	whole := math.Floor(seconds)
+	if math.IsNaN(whole) || whole < math.MinInt64 || whole >= -math.MinInt64 {
+		return &strconv.NumError{Func: "ParseFloat", Num: string(data), Err: strconv.ErrRange}
+	}
+	this.Time = time.Unix(int64(whole), int64((seconds-whole)*float64(time.Second)))
+	return nil
 }
 
 // MarshalJSON is the implementation of the json.Marshaler interface.
@@ -1,7 +1,9 @@
 package gojwt_test
 
 import (
+	"errors"
 	"github.com/tobyguelly/gojwt"
+	"strconv"
 	"testing"
 	"time"
 )
@@ -35,6 +37,7 @@ func TestTime_Unmarshal(t *testing.T) {
 	tests := []struct {
 		Input          string
 		ExpectedOutput *gojwt.Time
+		ExpectedError  error
 	}{
 		{
 			Input:          "0",
@@ -44,14 +47,44 @@ func TestTime_Unmarshal(t *testing.T) {
 			Input:          "1000",
 			ExpectedOutput: gojwt.Unix(1000),
 		},
+		{
+			Input:          "1000.5",
+			ExpectedOutput: gojwt.Unix(1000),
+		},
+		{
+			Input:          "1.3008e9",
+			ExpectedOutput: gojwt.Unix(1300800000),
+		},
+		{
+			Input:         "1e30",
+			ExpectedError: strconv.ErrRange,
+		},
+		{
+			Input:         "-1e30",
+			ExpectedError: strconv.ErrRange,
+		},
+		{
+			Input:         "9.3e18",
+			ExpectedError: strconv.ErrRange,
+		},
+		{
+			Input:         "1e400",
+			ExpectedError: strconv.ErrRange,
+		},
+		{
+			Input:         "NaN",
+			ExpectedError: strconv.ErrRange,
+		},
 	}
 	for i, test := range tests {
 		unmarshalled := gojwt.Now()
-		if err := unmarshalled.UnmarshalJSON([]byte(test.Input)); err == nil && unmarshalled.Time.Unix() == test.ExpectedOutput.Unix() {
+		err := unmarshalled.UnmarshalJSON([]byte(test.Input))
+		if test.ExpectedError != nil && errors.Is(err, test.ExpectedError) ||
+			test.ExpectedError == nil && err == nil && unmarshalled.Time.Unix() == test.ExpectedOutput.Unix() {
 			t.Logf("Passed %d/%d tests!", i+1, len(tests))
 		} else {
-			t.Errorf("Output and expected output did not match: %s\nFound:\t\t%d\nExpected:\t%d",
-				err, unmarshalled.Unix(), test.ExpectedOutput.Unix(),
+			t.Errorf("Output and expected output did not match: %s\nFound:\t\t%v %v\nExpected:\t%v %v",
+				test.Input, unmarshalled, err, test.ExpectedOutput, test.ExpectedError,
 			)
 		}
 	}
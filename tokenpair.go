@@ -0,0 +1,385 @@
+package gojwt
+
+import (
+	"container/heap"
+	"encoding/base64"
+	"errors"
+	"sync"
+	"time"
+)
+
+const (
+	// DefaultAccessTokenLifetime is the default lifetime of access tokens issued by a TokenPairIssuer.
+	DefaultAccessTokenLifetime = 15 * time.Minute
+
+	// DefaultRefreshTokenLifetime is the default lifetime of refresh tokens issued by a TokenPairIssuer.
+	DefaultRefreshTokenLifetime = 30 * 24 * time.Hour
+)
+
+const (
+	// familyClaim is the name of the custom claim holding the family ID of a token pair.
+	familyClaim = "fid"
+
+	// authTimeClaim is the name of the custom claim holding the time the token family has been created,
+	// like the auth_time claim of OpenID Connect.
+	authTimeClaim = "auth_time"
+)
+
+// TokenPair holds a short-lived access token and a long-lived refresh token, which share a family ID.
+type TokenPair struct {
+
+	// AccessToken is the signed access token, with the typ (Type) header TypAccessJWT.
+	AccessToken string
+
+	// AccessExpiry is the expiration time of the access token.
+	AccessExpiry time.Time
+
+	// RefreshToken is the signed refresh token, with the typ (Type) header TypRefreshJWT.
+	RefreshToken string
+
+	// RefreshExpiry is the expiration time of the refresh token.
+	RefreshExpiry time.Time
+
+	// FamilyID identifies all token pairs, which have been issued by refreshing the first token pair.
+	FamilyID string
+}
+
+// RefreshStore stores the current refresh token of every token family, to detect the reuse of rotated refresh tokens.
+// Implementations must be safe for concurrent use and must rotate the refresh tokens atomically.
+type RefreshStore interface {
+
+	// CreateFamily creates a new token family with its first refresh token, identified by its JWTID.
+	// The family may be removed after the expiry time.
+	CreateFamily(familyID, jti string, expiry time.Time) error
+
+	// Rotate replaces the current refresh token of a family with the next refresh token and extends the expiry time.
+	// Returns ErrRefReuse if the refresh token is not the current refresh token of the family
+	// or ErrTokRevk if the family does not exist, because it has been revoked or has expired.
+	Rotate(familyID, jti, next string, expiry time.Time) error
+
+	// RevokeFamily revokes a token family, so none of its refresh tokens can be used anymore.
+	RevokeFamily(familyID string) error
+}
+
+// FamilyID returns the family ID of a JWT issued by a TokenPairIssuer or an empty string.
+func FamilyID(jwt *JWT) string {
+	family, _ := jwt.Payload.GetCustom(familyClaim).(string)
+	return family
+}
+
+// TokenPairIssuer issues token pairs and refreshes them by rotating the refresh token.
+// If a refresh token is used again after it has been rotated, its whole family is revoked.
+// Access tokens of a revoked family stay valid until they expire.
+// A TokenPairIssuer can not be changed after its creation and is safe for concurrent use.
+type TokenPairIssuer struct {
+	signingKey      interface{}
+	verificationKey interface{}
+	store           RefreshStore
+	validator       *Validator
+	accessLifetime  time.Duration
+	refreshLifetime time.Duration
+	familyLifetime  time.Duration
+}
+
+// TokenPairOption configures a TokenPairIssuer when it is created with NewTokenPairIssuer.
+type TokenPairOption func(issuer *TokenPairIssuer)
+
+// NewTokenPairIssuer creates a new TokenPairIssuer, which signs tokens with a signing key and validates
+// refresh tokens with a verification key, like the private and the public key of a key pair or the same secret.
+// Without options, the refresh tokens are stored in a MemoryRefreshStore and the tokens expire after
+// DefaultAccessTokenLifetime and DefaultRefreshTokenLifetime and the lifetime of token families is not limited.
+func NewTokenPairIssuer(signingKey, verificationKey interface{}, options ...TokenPairOption) *TokenPairIssuer {
+	issuer := &TokenPairIssuer{
+		signingKey:      signingKey,
+		verificationKey: verificationKey,
+		store:           NewMemoryRefreshStore(),
+		validator:       NewValidator(),
+		accessLifetime:  DefaultAccessTokenLifetime,
+		refreshLifetime: DefaultRefreshTokenLifetime,
+	}
+	for _, option := range options {
+		option(issuer)
+	}
+	validator := *issuer.validator
+	validator.types = []string{TypRefreshJWT}
+	issuer.validator = &validator
+	return issuer
+}
+
+// UseRefreshStore sets the RefreshStore, which stores the current refresh token of every token family.
+func UseRefreshStore(store RefreshStore) TokenPairOption {
+	return func(issuer *TokenPairIssuer) {
+		issuer.store = store
+	}
+}
+
+// AccessTokenLifetime sets the lifetime of access tokens.
+func AccessTokenLifetime(lifetime time.Duration) TokenPairOption {
+	return func(issuer *TokenPairIssuer) {
+		issuer.accessLifetime = lifetime
+	}
+}
+
+// RefreshTokenLifetime sets the lifetime of refresh tokens, which is extended with every refresh.
+// Without FamilyLifetime, a token family, which is refreshed before its refresh token expires, never expires.
+func RefreshTokenLifetime(lifetime time.Duration) TokenPairOption {
+	return func(issuer *TokenPairIssuer) {
+		issuer.refreshLifetime = lifetime
+	}
+}
+
+// FamilyLifetime sets the maximum lifetime of a token family, counted from the auth_time (AuthTime) claim,
+// like an absolute session limit. The expiration times of the tokens are capped at the end of the family lifetime,
+// so the family can not be refreshed afterwards.
+func FamilyLifetime(lifetime time.Duration) TokenPairOption {
+	return func(issuer *TokenPairIssuer) {
+		issuer.familyLifetime = lifetime
+	}
+}
+
+// ValidateRefreshWith sets the Validator, which validates refresh tokens before they are rotated.
+// The accepted types of the Validator are replaced with TypRefreshJWT, so only refresh tokens are accepted.
+func ValidateRefreshWith(validator *Validator) TokenPairOption {
+	return func(issuer *TokenPairIssuer) {
+		issuer.validator = validator
+	}
+}
+
+// Issue issues a new token pair in a new token family, with the header and the claims of a template Builder,
+// like the algorithm, the issuer and the subject. The typ (Type) header and the jti (JWTID), iat (IssuedAt),
+// exp (ExpirationTime) and fid (FamilyID) claims are set for every token. The auth_time (AuthTime) claim is set
+// to the current time, unless the template holds it already. If the template is nil, NewJWT is used.
+// Returns a ValidationError matching ErrTokExpd if the auth_time claim of the template is older than the FamilyLifetime.
+func (this *TokenPairIssuer) Issue(template *Builder) (*TokenPair, error) {
+	jwt := NewJWT()
+	if template != nil {
+		jwt = template.JWT
+	}
+	family, err := randomID()
+	if err != nil {
+		return nil, err
+	}
+	pair, jti, err := this.issue(jwt, family)
+	if err != nil {
+		return nil, err
+	}
+	err = this.store.CreateFamily(family, jti, pair.RefreshExpiry)
+	if err != nil {
+		return nil, err
+	}
+	return pair, nil
+}
+
+// Refresh validates a refresh token and issues a new token pair in its family, with the header and the claims
+// of the refresh token, which is rotated and can not be used again.
+// Returns the errors of the Parse method of Validator, ErrInvTokTyp if the token is not a refresh token,
+// ErrMisTokClm if the token has no jti (JWTID) or fid (FamilyID) claim, ErrTokExpd if the FamilyLifetime
+// has passed, ErrTokRevk if the family has been
+// revoked or ErrRefReuse if the refresh token has already been rotated, in which case the family is revoked.
+func (this *TokenPairIssuer) Refresh(token string) (*TokenPair, error) {
+	jwt, err := this.validator.Parse(token, this.verificationKey)
+	if err != nil {
+		return nil, err
+	}
+	family := FamilyID(jwt)
+	if family == "" {
+		return nil, &ValidationError{Err: ErrMisTokClm, Claim: familyClaim}
+	}
+	if jwt.Payload.JWTID == "" {
+		return nil, &ValidationError{Err: ErrMisTokClm, Claim: "jti"}
+	}
+	template := JWT{Header: jwt.Header, Payload: jwt.Payload}
+	template.Header.KeyID = ""
+	pair, next, err := this.issue(template, family)
+	if err != nil {
+		return nil, err
+	}
+	err = this.store.Rotate(family, jwt.Payload.JWTID, next, pair.RefreshExpiry)
+	if errors.Is(err, ErrRefReuse) {
+		revokeErr := this.store.RevokeFamily(family)
+		if revokeErr != nil {
+			return nil, revokeErr
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+	return pair, nil
+}
+
+// RevokeFamily revokes a token family, like when a user logs out, so none of its refresh tokens can be used anymore.
+func (this *TokenPairIssuer) RevokeFamily(familyID string) error {
+	return this.store.RevokeFamily(familyID)
+}
+
+// issue signs an access and a refresh token from a template and returns them with the JWTID of the refresh token.
+// The expiration times are capped at the end of the family lifetime.
+func (this *TokenPairIssuer) issue(template JWT, family string) (*TokenPair, string, error) {
+	now := time.Now().Truncate(time.Second)
+	authTime, ok := familyAuthTime(&template.Payload)
+	if !ok {
+		authTime = now
+	}
+	pair := &TokenPair{
+		AccessExpiry:  now.Add(this.accessLifetime),
+		RefreshExpiry: now.Add(this.refreshLifetime),
+		FamilyID:      family,
+	}
+	if this.familyLifetime > 0 {
+		end := authTime.Add(this.familyLifetime)
+		if !now.Before(end) {
+			return nil, "", &ValidationError{Err: ErrTokExpd, Claim: authTimeClaim, Value: authTime, Expected: now.Add(-this.familyLifetime)}
+		}
+		if pair.AccessExpiry.After(end) {
+			pair.AccessExpiry = end
+		}
+		if pair.RefreshExpiry.After(end) {
+			pair.RefreshExpiry = end
+		}
+	}
+	access, _, err := this.sign(template, TypAccessJWT, family, now, authTime, pair.AccessExpiry)
+	if err != nil {
+		return nil, "", err
+	}
+	refresh, jti, err := this.sign(template, TypRefreshJWT, family, now, authTime, pair.RefreshExpiry)
+	if err != nil {
+		return nil, "", err
+	}
+	pair.AccessToken, pair.RefreshToken = access, refresh
+	return pair, jti, nil
+}
+
+// sign signs a copy of a template with the type, the family, the auth time and the lifetime of a token
+// and returns the token and its JWTID.
+func (this *TokenPairIssuer) sign(template JWT, typ, family string, now, authTime, expiry time.Time) (string, string, error) {
+	jti, err := randomID()
+	if err != nil {
+		return "", "", err
+	}
+	jwt := JWT{Header: template.Header, Payload: template.Payload}
+	jwt.Header.Type = typ
+	jwt.Payload.Custom = make(Map, len(template.Payload.Custom)+2)
+	for key, value := range template.Payload.Custom {
+		jwt.Payload.Custom[key] = value
+	}
+	jwt.Payload.SetCustom(familyClaim, family)
+	jwt.Payload.SetCustom(authTimeClaim, authTime.Unix())
+	jwt.Payload.JWTID = jti
+	jwt.Payload.IssuedAt = Wrap(now)
+	jwt.Payload.NotBefore = nil
+	jwt.Payload.ExpirationTime = Wrap(expiry)
+	token, err := jwt.SignParseWithKey(this.signingKey)
+	if err != nil {
+		return "", "", err
+	}
+	return token, jti, nil
+}
+
+// MemoryRefreshStore is a RefreshStore, which keeps the token families in memory
+// and removes them automatically once they have expired.
+type MemoryRefreshStore struct {
+	memoryStore
+	mutex    sync.Mutex
+	families map[string]*refreshFamily
+	expiry   expiryQueue
+}
+
+type refreshFamily struct {
+	current string
+	entry   *expiryEntry
+}
+
+// NewMemoryRefreshStore creates a new and empty MemoryRefreshStore with a list of options.
+func NewMemoryRefreshStore(options ...MemoryStoreOption) *MemoryRefreshStore {
+	return &MemoryRefreshStore{
+		memoryStore: newMemoryStore(options),
+		families:    make(map[string]*refreshFamily),
+	}
+}
+
+// CreateFamily is the implementation of the RefreshStore interface.
+// An existing family with the same ID is replaced.
+func (this *MemoryRefreshStore) CreateFamily(familyID, jti string, expiry time.Time) error {
+	this.mutex.Lock()
+	defer this.mutex.Unlock()
+	this.evict()
+	this.remove(familyID)
+	family := &refreshFamily{current: jti, entry: &expiryEntry{key: familyID, expiry: expiry}}
+	this.families[familyID] = family
+	heap.Push(&this.expiry, family.entry)
+	return nil
+}
+
+// Rotate is the implementation of the RefreshStore interface.
+func (this *MemoryRefreshStore) Rotate(familyID, jti, next string, expiry time.Time) error {
+	this.mutex.Lock()
+	defer this.mutex.Unlock()
+	this.evict()
+	family, ok := this.families[familyID]
+	if !ok {
+		return ErrTokRevk
+	}
+	if family.current != jti {
+		return ErrRefReuse
+	}
+	family.current = next
+	if expiry.After(family.entry.expiry) {
+		family.entry.expiry = expiry
+		heap.Fix(&this.expiry, family.entry.index)
+	}
+	return nil
+}
+
+// RevokeFamily is the implementation of the RefreshStore interface.
+func (this *MemoryRefreshStore) RevokeFamily(familyID string) error {
+	this.mutex.Lock()
+	defer this.mutex.Unlock()
+	this.remove(familyID)
+	return nil
+}
+
+// Len returns the amount of token families, which have not been revoked and have not expired yet.
+func (this *MemoryRefreshStore) Len() int {
+	this.mutex.Lock()
+	defer this.mutex.Unlock()
+	this.evict()
+	return len(this.families)
+}
+
+func (this *MemoryRefreshStore) remove(familyID string) {
+	if family, ok := this.families[familyID]; ok {
+		heap.Remove(&this.expiry, family.entry.index)
+		delete(this.families, familyID)
+	}
+}
+
+func (this *MemoryRefreshStore) evict() {
+	for _, entry := range this.expiry.popExpired(this.now()) {
+		delete(this.families, entry.key.(string))
+	}
+}
+
+// familyAuthTime returns the auth_time (AuthTime) claim of a payload and a bool, whether the payload holds it.
+// The claim is a float64 in parsed tokens and may be an int64 or a Time in templates.
+func familyAuthTime(payload *Payload) (time.Time, bool) {
+	switch value := payload.GetCustom(authTimeClaim).(type) {
+	case float64:
+		return time.Unix(int64(value), 0), true
+	case int64:
+		return time.Unix(value, 0), true
+	case *Time:
+		if value != nil {
+			return value.Time.Truncate(time.Second), true
+		}
+	}
+	return time.Time{}, false
+}
+
+// randomID returns a random and URL-safe identifier with 128 bits of entropy.
+func randomID() (string, error) {
+	id, err := randomBytes(16)
+	if err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(id), nil
+}
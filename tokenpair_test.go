@@ -0,0 +1,196 @@
+package gojwt_test
+
+import (
+	"errors"
+	"github.com/tobyguelly/gojwt"
+	"testing"
+	"time"
+)
+
+func TestTokenPairIssuer_Issue(t *testing.T) {
+	issuer := gojwt.NewTokenPairIssuer("secret", "secret", gojwt.AccessTokenLifetime(time.Minute))
+	pair, err := issuer.Issue(gojwt.WithBuilder().Subject("Joe").Custom("role", "admin"))
+	if err != nil {
+		t.Errorf("Failed test because of error: %s", err.Error())
+		t.FailNow()
+	}
+	tests := []struct {
+		Input        string
+		ExpectedType string
+		ExpectedExp  time.Time
+	}{
+		{
+			Input:        pair.AccessToken,
+			ExpectedType: gojwt.TypAccessJWT,
+			ExpectedExp:  pair.AccessExpiry,
+		},
+		{
+			Input:        pair.RefreshToken,
+			ExpectedType: gojwt.TypRefreshJWT,
+			ExpectedExp:  pair.RefreshExpiry,
+		},
+	}
+	for i, test := range tests {
+		jwt, err := gojwt.NewValidator(gojwt.ExpectTypes(test.ExpectedType)).Parse(test.Input, "secret")
+		if err == nil && jwt.Payload.Subject == "Joe" && jwt.Payload.GetCustom("role") == "admin" &&
+			gojwt.FamilyID(jwt) == pair.FamilyID && jwt.Payload.ExpirationTime.Time.Equal(test.ExpectedExp) &&
+			jwt.Payload.JWTID != "" && jwt.Payload.IssuedAt != nil {
+			t.Logf("Passed %d/%d tests!", i+1, len(tests))
+		} else {
+			t.Errorf("Output and expected output did not match: %s\nFound:\t\t%v %v\nExpected:\t%s %s",
+				test.Input, jwt, err, test.ExpectedType, pair.FamilyID,
+			)
+		}
+	}
+}
+
+func TestTokenPairIssuer_FamilyLifetime(t *testing.T) {
+	now := time.Now().Truncate(time.Second)
+	issuer := gojwt.NewTokenPairIssuer("secret", "secret", gojwt.FamilyLifetime(time.Hour))
+	tests := []struct {
+		AuthTime       time.Time
+		ExpectedError  error
+		ExpectedExpiry time.Time
+	}{
+		{
+			AuthTime:       now.Add(-59 * time.Minute),
+			ExpectedError:  nil,
+			ExpectedExpiry: now.Add(time.Minute),
+		},
+		{
+			AuthTime:      now.Add(-2 * time.Hour),
+			ExpectedError: gojwt.ErrTokExpd,
+		},
+	}
+	for i, test := range tests {
+		pair, err := issuer.Issue(gojwt.WithBuilder().Subject("Joe").Custom("auth_time", test.AuthTime.Unix()))
+		refreshed := pair
+		if err == nil {
+			refreshed, err = issuer.Refresh(pair.RefreshToken)
+		}
+		if errors.Is(err, test.ExpectedError) && (err != nil || pair.RefreshExpiry.Equal(test.ExpectedExpiry) &&
+			pair.AccessExpiry.Equal(test.ExpectedExpiry) && refreshed.RefreshExpiry.Equal(test.ExpectedExpiry)) {
+			t.Logf("Passed %d/%d tests!", i+1, len(tests))
+		} else {
+			t.Errorf("Output and expected output did not match: %v\nFound:\t\t%v %v\nExpected:\t%v %v",
+				test.AuthTime, refreshed, err, test.ExpectedExpiry, test.ExpectedError,
+			)
+		}
+	}
+}
+
+func TestTokenPairIssuer_ValidateWithKey(t *testing.T) {
+	pair, err := gojwt.NewTokenPairIssuer("secret", "secret").Issue(gojwt.WithBuilder().Subject("Joe"))
+	if err != nil {
+		t.Errorf("Failed test because of error: %s", err.Error())
+		t.FailNow()
+	}
+	tests := []struct {
+		Input         string
+		ExpectedError error
+	}{
+		{
+			Input:         pair.AccessToken,
+			ExpectedError: nil,
+		},
+		{
+			Input:         pair.RefreshToken,
+			ExpectedError: gojwt.ErrInvTokTyp,
+		},
+	}
+	for i, test := range tests {
+		jwt, err := gojwt.LoadJWT(test.Input)
+		if err == nil {
+			err = jwt.ValidateWithKey("secret")
+		}
+		if errors.Is(err, test.ExpectedError) {
+			t.Logf("Passed %d/%d tests!", i+1, len(tests))
+		} else {
+			t.Errorf("Output and expected output did not match: %s\nFound:\t\t%v\nExpected:\t%v",
+				test.Input, err, test.ExpectedError,
+			)
+		}
+	}
+}
+
+func TestTokenPairIssuer_Refresh(t *testing.T) {
+	store := gojwt.NewMemoryRefreshStore()
+	issuer := gojwt.NewTokenPairIssuer("secret", "secret", gojwt.UseRefreshStore(store))
+	first, err := issuer.Issue(gojwt.WithBuilder().Subject("Joe"))
+	if err != nil {
+		t.Errorf("Failed test because of error: %s", err.Error())
+		t.FailNow()
+	}
+	revoked, err := issuer.Issue(gojwt.WithBuilder().Subject("Jane"))
+	if err != nil {
+		t.Errorf("Failed test because of error: %s", err.Error())
+		t.FailNow()
+	}
+	err = issuer.RevokeFamily(revoked.FamilyID)
+	if err != nil {
+		t.Errorf("Failed test because of error: %s", err.Error())
+		t.FailNow()
+	}
+	expired, err := gojwt.NewTokenPairIssuer("secret", "secret", gojwt.UseRefreshStore(store),
+		gojwt.RefreshTokenLifetime(-time.Minute),
+	).Issue(nil)
+	if err != nil {
+		t.Errorf("Failed test because of error: %s", err.Error())
+		t.FailNow()
+	}
+	current := first.RefreshToken
+	tests := []struct {
+		Input         func() string
+		ExpectedError error
+	}{
+		{
+			Input:         func() string { return current },
+			ExpectedError: nil,
+		},
+		{
+			Input:         func() string { return current },
+			ExpectedError: nil,
+		},
+		{
+			Input:         func() string { return first.AccessToken },
+			ExpectedError: gojwt.ErrInvTokTyp,
+		},
+		{
+			Input:         func() string { return first.RefreshToken },
+			ExpectedError: gojwt.ErrRefReuse,
+		},
+		{
+			Input:         func() string { return current },
+			ExpectedError: gojwt.ErrTokRevk,
+		},
+		{
+			Input:         func() string { return revoked.RefreshToken },
+			ExpectedError: gojwt.ErrTokRevk,
+		},
+		{
+			Input:         func() string { return expired.RefreshToken },
+			ExpectedError: gojwt.ErrTokExpd,
+		},
+		{
+			Input:         func() string { return "abc" },
+			ExpectedError: gojwt.ErrBadJWTTok,
+		},
+	}
+	for i, test := range tests {
+		input := test.Input()
+		pair, err := issuer.Refresh(input)
+		if err == nil {
+			current = pair.RefreshToken
+		}
+		if errors.Is(err, test.ExpectedError) && (err != nil || pair.FamilyID == first.FamilyID && pair.RefreshToken != input) {
+			t.Logf("Passed %d/%d tests!", i+1, len(tests))
+		} else {
+			t.Errorf("Output and expected output did not match: %s\nFound:\t\t%v\nExpected:\t%v",
+				input, err, test.ExpectedError,
+			)
+		}
+	}
+	if store.Len() != 0 {
+		t.Errorf("Output and expected output did not match: %s\nFound:\t\t%d\nExpected:\t%d", "Len", store.Len(), 0)
+	}
+}
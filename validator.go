@@ -0,0 +1,258 @@
+package gojwt
+
+import (
+	"strings"
+	"time"
+)
+
+// Validator validates the signature and the claims of JWTs based on a set of options.
+// A Validator can not be changed after its creation and is safe for concurrent use.
+type Validator struct {
+	algorithms       []string
+	issuers          []string
+	audiences        []string
+	types            []string
+	requiredClaims   []string
+	leeway           time.Duration
+	maxAge           time.Duration
+	registry         *Registry
+	revocations      RevocationStore
+	cutoffs          CutoffStore
+	rejectMissingIat bool
+	now              func() time.Time
+}
+
+// ValidatorOption configures a Validator when it is created with NewValidator.
+type ValidatorOption func(validator *Validator)
+
+// NewValidator creates a new Validator from a list of options.
+// Without options, the Validator checks the signature and the exp (ExpirationTime)
+// and nbf (NotBefore) claims, with the same comparisons as the Validate method of JWT.
+func NewValidator(options ...ValidatorOption) *Validator {
+	validator := &Validator{
+		registry: DefaultRegistry,
+		now:      time.Now,
+	}
+	for _, option := range options {
+		option(validator)
+	}
+	return validator
+}
+
+// AllowAlgorithms sets the accepted values of the alg (Algorithm) header.
+// Tokens with other algorithms are rejected with ErrAlgNotAll before the signature is verified.
+func AllowAlgorithms(algs ...string) ValidatorOption {
+	return func(validator *Validator) {
+		validator.algorithms = append([]string(nil), algs...)
+	}
+}
+
+// ExpectIssuers sets the accepted values of the iss (Issuer) claim.
+func ExpectIssuers(issuers ...string) ValidatorOption {
+	return func(validator *Validator) {
+		validator.issuers = append([]string(nil), issuers...)
+	}
+}
+
+// ExpectAudience sets the expected values of the aud (Audience) claim.
+// The token is accepted if its audience contains any of them.
+func ExpectAudience(audiences ...string) ValidatorOption {
+	return func(validator *Validator) {
+		validator.audiences = append([]string(nil), audiences...)
+	}
+}
+
+// ExpectTypes sets the accepted values of the typ (Type) header, which are compared case-insensitively.
+// Without accepted types, every type except TypRefreshJWT is accepted, so refresh tokens can not be used as access tokens.
+func ExpectTypes(types ...string) ValidatorOption {
+	return func(validator *Validator) {
+		validator.types = append([]string(nil), types...)
+	}
+}
+
+// RequireClaims sets the names of claims, which must be present in the token payload.
+func RequireClaims(names ...string) ValidatorOption {
+	return func(validator *Validator) {
+		validator.requiredClaims = append([]string(nil), names...)
+	}
+}
+
+// AllowLeeway sets the tolerated clock skew for the exp (ExpirationTime), nbf (NotBefore) and iat (IssuedAt) claims.
+func AllowLeeway(leeway time.Duration) ValidatorOption {
+	return func(validator *Validator) {
+		validator.leeway = leeway
+	}
+}
+
+// MaxTokenAge sets the maximum age of a token, based on its iat (IssuedAt) claim.
+// Tokens without the iat claim are rejected if a maximum age is set.
+func MaxTokenAge(maxAge time.Duration) ValidatorOption {
+	return func(validator *Validator) {
+		validator.maxAge = maxAge
+	}
+}
+
+// UseRegistry sets the Registry used to look up the Verifier for the algorithm of a token.
+func UseRegistry(registry *Registry) ValidatorOption {
+	return func(validator *Validator) {
+		validator.registry = registry
+	}
+}
+
+// UseClock sets the function returning the current time, which defaults to time.Now.
+func UseClock(now func() time.Time) ValidatorOption {
+	return func(validator *Validator) {
+		validator.now = now
+	}
+}
+
+// Parse loads a JWT from a JWT string and validates it with a key.
+// Returns the JWT and the first error of LoadJWT or Validate.
+func (this *Validator) Parse(token string, key interface{}) (*JWT, error) {
+	jwt, err := LoadJWT(token)
+	if err != nil {
+		return jwt, err
+	}
+	return jwt, this.Validate(jwt, key)
+}
+
+// Validate validates the signature of a JWT with a key and checks all claims, using the
+// Verifier of the Registry for the algorithm in the Header.
+// If the key is a KeyProvider, like a JWKSet, the key is selected like by the ValidateWithKeySet method of JWT.
+// Returns ErrAlgNotAll if the algorithm is not allowed, the same errors as the ValidateWithKey method of JWT
+// and the errors of ValidateClaims.
+func (this *Validator) Validate(jwt *JWT, key interface{}) error {
+	if len(this.algorithms) > 0 && !contains(this.algorithms, jwt.Header.Algorithm) {
+		return ErrAlgNotAll
+	}
+	if provider, ok := key.(KeyProvider); ok {
+		err := verifyWithKeys(this.registry, jwt, provider)
+		if err != nil {
+			return err
+		}
+		return this.ValidateClaims(jwt)
+	}
+	verifier, err := this.registry.Verifier(jwt.Header.Algorithm, key)
+	if err != nil {
+		return err
+	}
+	return this.ValidateWith(jwt, verifier)
+}
+
+// ValidateWith validates the signature of a JWT with a Verifier and checks all claims.
+func (this *Validator) ValidateWith(jwt *JWT, verifier Verifier) error {
+	if len(this.algorithms) > 0 && !contains(this.algorithms, jwt.Header.Algorithm) {
+		return ErrAlgNotAll
+	}
+	err := jwt.VerifySignature(verifier)
+	if err != nil {
+		return err
+	}
+	return this.ValidateClaims(jwt)
+}
+
+// ValidateClaims checks the header and the claims of a JWT without validating its signature.
+// If any checks fail, ValidationErrors holding a ValidationError for every failed check are returned,
+// with ErrInvTokTyp if the typ header is not allowed or is TypRefreshJWT without accepted types, ErrMisTokClm if a required claim is missing,
+// ErrTokExpd if the token has expired, ErrTokNotAct if the token is not valid yet, ErrInvTokIat if the token
+// was issued in the future or is older than the maximum token age, ErrInvTokIss if the issuer is not expected
+// and ErrInvTokAud if the audience does not contain an expected audience.
+// If a RevocationStore is set, ErrTokRevk is added if the token has been revoked and if a CutoffStore is set,
+// ErrTokIssCut is added if the token has been issued before the cutoff of its subject.
+// If a store fails, an error matching ErrStoreUnav and the error of the store is returned instead.
+func (this *Validator) ValidateClaims(jwt *JWT) error {
+	var errs ValidationErrors
+	now := this.now()
+	payload := &jwt.Payload
+	if len(this.types) > 0 && !containsFold(this.types, jwt.Header.Type) {
+		errs = append(errs, &ValidationError{Err: ErrInvTokTyp, Claim: "typ", Value: jwt.Header.Type, Expected: this.types})
+	} else if len(this.types) == 0 && isRefreshToken(&jwt.Header) {
+		errs = append(errs, &ValidationError{Err: ErrInvTokTyp, Claim: "typ", Value: jwt.Header.Type})
+	}
+	for _, name := range this.requiredClaims {
+		if !payload.HasClaim(name) {
+			errs = append(errs, &ValidationError{Err: ErrMisTokClm, Claim: name})
+		}
+	}
+	if hasExpired(payload.ExpirationTime, now, this.leeway) {
+		errs = append(errs, &ValidationError{Err: ErrTokExpd, Claim: "exp", Value: payload.ExpirationTime.Time, Expected: now})
+	}
+	if isNotActive(payload.NotBefore, now, this.leeway) {
+		errs = append(errs, &ValidationError{Err: ErrTokNotAct, Claim: "nbf", Value: payload.NotBefore.Time, Expected: now})
+	}
+	if payload.IssuedAt != nil && now.Before(payload.IssuedAt.Time.Add(-this.leeway)) {
+		errs = append(errs, &ValidationError{Err: ErrInvTokIat, Claim: "iat", Value: payload.IssuedAt.Time, Expected: now})
+	}
+	if this.maxAge > 0 {
+		if payload.IssuedAt == nil {
+			errs = append(errs, &ValidationError{Err: ErrMisTokClm, Claim: "iat"})
+		} else if !now.Before(payload.IssuedAt.Time.Add(this.maxAge + this.leeway)) {
+			errs = append(errs, &ValidationError{Err: ErrInvTokIat, Claim: "iat", Value: payload.IssuedAt.Time, Expected: now.Add(-this.maxAge)})
+		}
+	}
+	if len(this.issuers) > 0 && !contains(this.issuers, payload.Issuer) {
+		errs = append(errs, &ValidationError{Err: ErrInvTokIss, Claim: "iss", Value: payload.Issuer, Expected: this.issuers})
+	}
+	if len(this.audiences) > 0 && !payload.Audience.ContainsAny(this.audiences...) {
+		var audiences []string
+		if payload.Audience != nil {
+			audiences = payload.Audience.Values
+		}
+		errs = append(errs, &ValidationError{Err: ErrInvTokAud, Claim: "aud", Value: audiences, Expected: this.audiences})
+	}
+	if this.revocations != nil && payload.JWTID != "" {
+		revoked, err := this.revocations.IsRevoked(payload.JWTID, payload.Subject)
+		if err != nil {
+			return &storeError{err: err}
+		}
+		if revoked {
+			errs = append(errs, &ValidationError{Err: ErrTokRevk, Claim: "jti", Value: payload.JWTID})
+		}
+	}
+	if this.cutoffs != nil {
+		cutoffErr, err := checkCutoff(this.cutoffs, payload, this.rejectMissingIat)
+		if err != nil {
+			return &storeError{err: err}
+		}
+		if cutoffErr != nil {
+			errs = append(errs, cutoffErr)
+		}
+	}
+	return errs.orNil()
+}
+
+// isRefreshToken returns a bool, whether the typ (Type) header is TypRefreshJWT. Refresh tokens are only accepted
+// if TypRefreshJWT is expected explicitly, so they can not be used as access tokens.
+func isRefreshToken(header *Header) bool {
+	return strings.EqualFold(header.Type, TypRefreshJWT)
+}
+
+// hasExpired returns a bool, whether the exp (ExpirationTime) claim has passed at a time, which is the case
+// from the expiration time on, as specified in RFC 7519 section 4.1.4.
+func hasExpired(exp *Time, now time.Time, leeway time.Duration) bool {
+	return exp != nil && !now.Before(exp.Time.Add(leeway))
+}
+
+// isNotActive returns a bool, whether the nbf (NotBefore) claim has not passed at a time.
+// As specified in RFC 7519 section 4.1.5, the token is valid from the not before time on.
+func isNotActive(nbf *Time, now time.Time, leeway time.Duration) bool {
+	return nbf != nil && now.Before(nbf.Time.Add(-leeway))
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+func containsFold(values []string, value string) bool {
+	for _, v := range values {
+		if strings.EqualFold(v, value) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,242 @@
+package gojwt_test
+
+import (
+	"errors"
+	"github.com/tobyguelly/gojwt"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestValidator_Validate(t *testing.T) {
+	now := time.Unix(1000, 0)
+	clock := gojwt.UseClock(func() time.Time {
+		return now
+	})
+	tests := []struct {
+		Validator     *gojwt.Validator
+		Input         *gojwt.Builder
+		Key           interface{}
+		ExpectedError error
+	}{
+		{
+			Validator:     gojwt.NewValidator(clock),
+			Input:         gojwt.WithBuilder(),
+			Key:           "secret",
+			ExpectedError: nil,
+		},
+		{
+			Validator:     gojwt.NewValidator(clock),
+			Input:         gojwt.WithBuilder(),
+			Key:           "wrong",
+			ExpectedError: gojwt.ErrInvSecKey,
+		},
+		{
+			Validator:     gojwt.NewValidator(clock, gojwt.ExpectIssuers("foo", "gojwt")),
+			Input:         gojwt.WithBuilder(),
+			Key:           "secret",
+			ExpectedError: nil,
+		},
+		{
+			Validator:     gojwt.NewValidator(clock, gojwt.ExpectIssuers("foo")),
+			Input:         gojwt.WithBuilder(),
+			Key:           "secret",
+			ExpectedError: gojwt.ErrInvTokIss,
+		},
+		{
+			Validator:     gojwt.NewValidator(clock, gojwt.ExpectAudience("api")),
+			Input:         gojwt.WithBuilder().Audience("api"),
+			Key:           "secret",
+			ExpectedError: nil,
+		},
+		{
+			Validator:     gojwt.NewValidator(clock, gojwt.ExpectAudience("api")),
+			Input:         gojwt.WithBuilder().Audience("web"),
+			Key:           "secret",
+			ExpectedError: gojwt.ErrInvTokAud,
+		},
+		{
+			Validator:     gojwt.NewValidator(clock, gojwt.ExpectAudience("api")),
+			Input:         gojwt.WithBuilder(),
+			Key:           "secret",
+			ExpectedError: gojwt.ErrInvTokAud,
+		},
+		{
+			Validator:     gojwt.NewValidator(clock),
+			Input:         gojwt.WithBuilder().ExpirationTime(now.Add(-time.Second)),
+			Key:           "secret",
+			ExpectedError: gojwt.ErrInvTokPrd,
+		},
+		{
+			Validator:     gojwt.NewValidator(clock, gojwt.AllowLeeway(time.Minute)),
+			Input:         gojwt.WithBuilder().ExpirationTime(now.Add(-time.Second)),
+			Key:           "secret",
+			ExpectedError: nil,
+		},
+		{
+			Validator:     gojwt.NewValidator(clock),
+			Input:         gojwt.WithBuilder().NotBefore(now.Add(time.Second * 30)),
+			Key:           "secret",
+			ExpectedError: gojwt.ErrInvTokPrd,
+		},
+		{
+			Validator:     gojwt.NewValidator(clock, gojwt.AllowLeeway(time.Minute)),
+			Input:         gojwt.WithBuilder().NotBefore(now.Add(time.Second * 30)),
+			Key:           "secret",
+			ExpectedError: nil,
+		},
+		{
+			Validator:     gojwt.NewValidator(clock),
+			Input:         gojwt.WithBuilder().IssuedAt(now.Add(time.Hour)),
+			Key:           "secret",
+			ExpectedError: gojwt.ErrInvTokIat,
+		},
+		{
+			Validator:     gojwt.NewValidator(clock, gojwt.MaxTokenAge(time.Minute)),
+			Input:         gojwt.WithBuilder().IssuedAt(now.Add(-time.Second * 30)),
+			Key:           "secret",
+			ExpectedError: nil,
+		},
+		{
+			Validator:     gojwt.NewValidator(clock, gojwt.MaxTokenAge(time.Minute)),
+			Input:         gojwt.WithBuilder().IssuedAt(now.Add(-time.Hour)),
+			Key:           "secret",
+			ExpectedError: gojwt.ErrInvTokIat,
+		},
+		{
+			Validator:     gojwt.NewValidator(clock, gojwt.MaxTokenAge(time.Minute)),
+			Input:         gojwt.WithBuilder(),
+			Key:           "secret",
+			ExpectedError: gojwt.ErrMisTokClm,
+		},
+		{
+			Validator:     gojwt.NewValidator(clock, gojwt.RequireClaims("sub", "role")),
+			Input:         gojwt.WithBuilder().Subject("admin").Custom("role", "owner"),
+			Key:           "secret",
+			ExpectedError: nil,
+		},
+		{
+			Validator:     gojwt.NewValidator(clock, gojwt.RequireClaims("sub", "role")),
+			Input:         gojwt.WithBuilder().Subject("admin"),
+			Key:           "secret",
+			ExpectedError: gojwt.ErrMisTokClm,
+		},
+		{
+			Validator:     gojwt.NewValidator(clock, gojwt.ExpectTypes("at+jwt", gojwt.TypJWT)),
+			Input:         gojwt.WithBuilder(),
+			Key:           "secret",
+			ExpectedError: nil,
+		},
+		{
+			Validator:     gojwt.NewValidator(clock, gojwt.ExpectTypes("at+jwt")),
+			Input:         gojwt.WithBuilder(),
+			Key:           "secret",
+			ExpectedError: gojwt.ErrInvTokTyp,
+		},
+		{
+			Validator:     gojwt.NewValidator(clock, gojwt.AllowAlgorithms(gojwt.AlgHS256)),
+			Input:         gojwt.WithBuilder(),
+			Key:           "secret",
+			ExpectedError: nil,
+		},
+		{
+			Validator:     gojwt.NewValidator(clock, gojwt.AllowAlgorithms(gojwt.AlgRS256)),
+			Input:         gojwt.WithBuilder(),
+			Key:           "secret",
+			ExpectedError: gojwt.ErrAlgNotAll,
+		},
+		{
+			Validator:     gojwt.NewValidator(clock),
+			Input:         gojwt.WithBuilder(),
+			Key:           gojwt.BindKey("secret", gojwt.AlgHS512),
+			ExpectedError: gojwt.ErrAlgNotAll,
+		},
+		{
+			Validator:     gojwt.NewValidator(clock, gojwt.UseRegistry(&gojwt.Registry{})),
+			Input:         gojwt.WithBuilder(),
+			Key:           "secret",
+			ExpectedError: gojwt.ErrAlgNotImp,
+		},
+	}
+	for i, test := range tests {
+		token, err := test.Input.Sign("secret")
+		if err != nil {
+			t.Errorf("Failed test because of error: %s", err.Error())
+			t.FailNow()
+		}
+		_, err = test.Validator.Parse(token, test.Key)
+		if errors.Is(err, test.ExpectedError) {
+			t.Logf("Passed %d/%d tests!", i+1, len(tests))
+		} else {
+			t.Errorf("Output and expected output did not match: %s\nFound:\t\t%v\nExpected:\t%v",
+				token, err, test.ExpectedError,
+			)
+		}
+	}
+}
+
+func TestValidator_Period(t *testing.T) {
+	now := time.Now().Truncate(time.Second)
+	tests := []struct {
+		Input         *gojwt.Builder
+		ExpectedError error
+	}{
+		{
+			Input:         gojwt.WithBuilder().NotBefore(now),
+			ExpectedError: nil,
+		},
+		{
+			Input:         gojwt.WithBuilder().NotBefore(now.Add(time.Minute)),
+			ExpectedError: gojwt.ErrTokNotAct,
+		},
+		{
+			Input:         gojwt.WithBuilder().ExpirationTime(now),
+			ExpectedError: gojwt.ErrTokExpd,
+		},
+		{
+			Input:         gojwt.WithBuilder().ExpirationTime(now.Add(time.Minute)),
+			ExpectedError: nil,
+		},
+	}
+	for i, test := range tests {
+		token, err := test.Input.Sign("secret")
+		if err != nil {
+			t.Errorf("Failed test because of error: %s", err.Error())
+			t.FailNow()
+		}
+		jwt, validatorErr := gojwt.NewValidator().Parse(token, "secret")
+		jwtErr := jwt.Validate("secret")
+		if errors.Is(validatorErr, test.ExpectedError) && errors.Is(jwtErr, test.ExpectedError) &&
+			jwt.IsExpired() == (test.ExpectedError != nil) {
+			t.Logf("Passed %d/%d tests!", i+1, len(tests))
+		} else {
+			t.Errorf("Output and expected output did not match: %s\nFound:\t\t%v %v\nExpected:\t%v",
+				token, validatorErr, jwtErr, test.ExpectedError,
+			)
+		}
+	}
+}
+
+func TestValidator_Concurrency(t *testing.T) {
+	validator := gojwt.NewValidator(
+		gojwt.ExpectIssuers("gojwt"),
+		gojwt.MaxTokenAge(time.Minute),
+	)
+	token, err := gojwt.WithBuilder().IssuedNow().ExpiresIn(time.Minute).Sign("secret")
+	if err != nil {
+		t.Errorf("Failed test because of error: %s", err.Error())
+		t.FailNow()
+	}
+	var wg sync.WaitGroup
+	for i := 0; i < 16; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := validator.Parse(token, "secret")
+			if err != nil {
+				t.Errorf("Failed test because of error: %s", err.Error())
+			}
+		}()
+	}
+	wg.Wait()
+}
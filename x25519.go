@@ -0,0 +1,50 @@
+package gojwt
+
+import (
+	"crypto"
+	"crypto/subtle"
+	"golang.org/x/crypto/curve25519"
+)
+
+const (
+	// X25519KeySize is the size of X25519 public and private keys in bytes.
+	X25519KeySize = 32
+)
+
+// X25519PublicKey is a public key for the X25519 key agreement specified in RFC 7748,
+// which is used for ECDH-ES key agreement with octet key pairs.
+type X25519PublicKey []byte
+
+// X25519PrivateKey is a private key for the X25519 key agreement specified in RFC 7748,
+// which is used for ECDH-ES key agreement with octet key pairs.
+type X25519PrivateKey []byte
+
+// GenerateX25519Key generates a random X25519 private key.
+func GenerateX25519Key() (X25519PrivateKey, error) {
+	key, err := randomBytes(X25519KeySize)
+	if err != nil {
+		return nil, err
+	}
+	return X25519PrivateKey(key), nil
+}
+
+// Public returns the X25519PublicKey of the private key.
+func (this X25519PrivateKey) Public() crypto.PublicKey {
+	publicKey, err := curve25519.X25519(this, curve25519.Basepoint)
+	if err != nil {
+		return nil
+	}
+	return X25519PublicKey(publicKey)
+}
+
+// Equal returns a bool, whether the private key is equal to another private key.
+func (this X25519PrivateKey) Equal(x crypto.PrivateKey) bool {
+	other, ok := x.(X25519PrivateKey)
+	return ok && subtle.ConstantTimeCompare(this, other) == 1
+}
+
+// Equal returns a bool, whether the public key is equal to another public key.
+func (this X25519PublicKey) Equal(x crypto.PublicKey) bool {
+	other, ok := x.(X25519PublicKey)
+	return ok && subtle.ConstantTimeCompare(this, other) == 1
+}
@@ -0,0 +1,40 @@
+package gojwt_test
+
+import (
+	"encoding/hex"
+	"github.com/tobyguelly/gojwt"
+	"testing"
+)
+
+func TestX25519PrivateKey_Public(t *testing.T) {
+	// The test vectors of RFC 7748 section 6.1.
+	tests := []struct {
+		Input          string
+		ExpectedOutput string
+	}{
+		{
+			Input:          "77076d0a7318a57d3c16c17251b26645df4c2f87ebc0992ab177fba51db92c2a",
+			ExpectedOutput: "8520f0098930a754748b7ddcb43ef75a0dbf3a0d26381af4eba4a98eaa9b4e6a",
+		},
+		{
+			Input:          "5dab087e624a8a4b79e17f8b83800ee66f3bb1292618b6fd1c2f8b27ff88e0eb",
+			ExpectedOutput: "de9edb7d7b7dc1b4d35b61c2ece435373f8343c85b78674dadfc7e146f882b4f",
+		},
+	}
+	for i, test := range tests {
+		key, err := hex.DecodeString(test.Input)
+		if err != nil {
+			t.Errorf("Failed test because of error: %s", err.Error())
+			continue
+		}
+		publicKey, _ := gojwt.X25519PrivateKey(key).Public().(gojwt.X25519PublicKey)
+		res := hex.EncodeToString(publicKey)
+		if res == test.ExpectedOutput {
+			t.Logf("Passed %d/%d tests!", i+1, len(tests))
+		} else {
+			t.Errorf("Output and expected output did not match: %s\nFound:\t\t%s\nExpected:\t%s",
+				test.Input, res, test.ExpectedOutput,
+			)
+		}
+	}
+}